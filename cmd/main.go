@@ -1,17 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/user"
 	_ "project/docs"
-	"project/internal/routes"
-	"project/internal/utils/validator"
+	"project/internal/container"
+	"project/internal/modules"
+	"project/internal/service"
 	"project/pkg/config"
 	"project/pkg/database"
-	"project/pkg/seeder"
+	"project/pkg/logger"
+	"time"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	fiberSwagger "github.com/swaggo/fiber-swagger"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
 )
 
 // @title Sat Net Base User Management API
@@ -23,51 +27,106 @@ import (
 // @in header
 // @name Authorization
 func main() {
+	app := &cli.App{
+		Name:  "project",
+		Usage: "User management API server and management tools",
+		// Tanpa subcommand, jalankan server seperti sebelumnya
+		Action: serve,
+		Commands: []*cli.Command{
+			serveCommand(),
+			migrateCommand(),
+			seedCommand(),
+			userCommand(),
+			roleCommand(),
+			tokenCommand(),
+			generateCommand(),
+		},
+	}
+
+	err := app.RunContext(service.WithActor(context.Background(), cliActor()), os.Args)
+
+	// Kirim sisa log di buffer (Elasticsearch) sebelum proses berhenti
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	logger.Close(ctx)
+	cancel()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// bootstrap memuat config dan membuka koneksi database
+func bootstrap() (*config.Config, *gorm.DB, error) {
 	// Load config
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Error loading config: %v", err)
+		return nil, nil, cli.Exit("Error loading config: "+err.Error(), 1)
+	}
+
+	// Logger terstruktur (log/slog) dengan level per komponen dari logging.*
+	if err := logger.Setup(loggerOptions(cfg)); err != nil {
+		return nil, nil, cli.Exit("Error configuring logger: "+err.Error(), 1)
 	}
 
 	// Inisialisasi koneksi database
 	db, err := database.ConnectDB(cfg)
 	if err != nil {
-		log.Fatalf("Error connecting to database: %v", err)
+		return nil, nil, cli.Exit("Error connecting to database: "+err.Error(), 1)
 	}
 
-	// userRepo := repository.NewUserRepository(db)
-	// userService := service.NewUserService(userRepo)
-	// userHandler := handler.NewUserHandler(userService)
+	return cfg, db, nil
+}
 
-	// Migrate the database
-	if err := database.MigrateDatabase(db); err != nil {
-		log.Fatalf("Database migration failed: %v", err)
+// loggerOptions menyusun opsi logger dari logging.*; dipakai saat start dan saat config di-reload
+func loggerOptions(cfg *config.Config) logger.Options {
+	options := logger.Options{
+		Level:  cfg.Logging.Level,
+		Format: cfg.Logging.Format,
+		Levels: cfg.Logging.Levels,
 	}
-
-	// Seeder untuk membuat user super admin
-	if err := seeder.SeedSuperAdmin(db, "passwordadmin"); err != nil {
-		log.Fatalf("Error seeding super admin: %v", err)
+	if cfg.Logging.ELKEnabled {
+		options.Elasticsearch = &logger.ElasticsearchOptions{
+			URL:           cfg.Logging.ELKHost,
+			Index:         cfg.Logging.ELKIndex,
+			BatchSize:     cfg.Logging.ELKBatchSize,
+			FlushInterval: cfg.Logging.ELKFlushInterval,
+			BufferSize:    cfg.Logging.ELKBufferSize,
+			MaxRetries:    cfg.Logging.ELKMaxRetries,
+		}
 	}
+	return options
+}
 
-	// Inisialisasi Fiber
-	app := fiber.New()
-
-	// // Inisialisasi validator
-	// app.Validator = validator.NewValidator()
-	// Initialize the validator
-	validator.InitValidator()
-
-	// Tambahkan middleware logger untuk mencatat semua request
-	app.Use(logger.New())
-
-	// Initialize routes dan injeksikan dependensi
-	routes.InitializeRoutes(app, db, cfg)
+// bootstrapContainer memuat config dan database, menyusun modul aplikasi untuk
+// perintah CLI (tanpa metrics dan route) lalu menjalankan hook start-nya, contoh
+// aturan validasi yang memeriksa database. Panggil Stop setelah perintah selesai.
+func bootstrapContainer(ctx context.Context) (*config.Config, *container.Container, error) {
+	cfg, db, err := bootstrap()
+	if err != nil {
+		return nil, nil, err
+	}
+	appContainer := container.New(modules.Default(cfg, db, nil)...)
+	if err := appContainer.Start(ctx); err != nil {
+		return nil, nil, cli.Exit("Error starting application: "+err.Error(), 1)
+	}
+	return cfg, appContainer, nil
+}
 
-	// Swagger route
-	app.Get("/swagger/*", fiberSwagger.WrapHandler)
+// resolve mengambil dependency bertipe T dari container untuk perintah CLI
+func resolve[T any](appContainer *container.Container) (T, error) {
+	value, err := container.Resolve[T](appContainer)
+	if err != nil {
+		return value, cli.Exit("Error initializing dependencies: "+err.Error(), 1)
+	}
+	return value, nil
+}
 
-	// Start server
-	if err := app.Listen(":" + cfg.App.Port); err != nil {
-		log.Fatalf("Error starting server: %v", err)
+// cliActor adalah pelaku yang dicatat di audit log untuk perintah CLI
+// (user sistem operasi yang menjalankan perintah)
+func cliActor() service.Actor {
+	username := "unknown"
+	if current, err := user.Current(); err == nil {
+		username = current.Username
 	}
+	return service.Actor{ID: "cli", Username: username}
 }
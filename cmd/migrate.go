@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"project/pkg/database"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Manage database schema migrations",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "up",
+				Usage: "Apply all pending migrations",
+				Action: withMigrator(func(c *cli.Context, migrator *database.Migrator) error {
+					return migrator.Up(c.Context)
+				}),
+			},
+			{
+				Name:      "down",
+				Usage:     "Roll back the last applied migration(s)",
+				ArgsUsage: "[steps]",
+				Action: withMigrator(func(c *cli.Context, migrator *database.Migrator) error {
+					steps := 1
+					if c.Args().Present() {
+						n, err := strconv.Atoi(c.Args().First())
+						if err != nil || n < 1 {
+							return cli.Exit(fmt.Sprintf("Invalid steps %q", c.Args().First()), 2)
+						}
+						steps = n
+					}
+					return migrator.Down(c.Context, steps)
+				}),
+			},
+			{
+				Name:      "to",
+				Usage:     "Migrate up or down to a version (0 rolls back everything)",
+				ArgsUsage: "<version>",
+				Action: withMigrator(func(c *cli.Context, migrator *database.Migrator) error {
+					version, err := strconv.ParseUint(c.Args().First(), 10, 0)
+					if err != nil {
+						return cli.Exit(fmt.Sprintf("Invalid version %q", c.Args().First()), 2)
+					}
+					return migrator.To(c.Context, uint(version))
+				}),
+			},
+			{
+				Name:  "status",
+				Usage: "Show applied and pending migrations",
+				Action: withMigrator(func(c *cli.Context, migrator *database.Migrator) error {
+					statuses, err := migrator.Status(c.Context)
+					if errors.Is(err, database.ErrNotInitialised) {
+						return cli.Exit("Database is not initialised: run \"migrate up\" first", 1)
+					}
+					if err != nil {
+						return err
+					}
+
+					for _, status := range statuses {
+						appliedAt := "pending"
+						if status.Applied {
+							appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+						}
+						fmt.Fprintf(c.App.Writer, "%04d  %-30s  %s\n", status.Version, status.Name, appliedAt)
+					}
+					return nil
+				}),
+			},
+		},
+	}
+}
+
+// withMigrator menyiapkan Migrator sebelum menjalankan action
+func withMigrator(action func(c *cli.Context, migrator *database.Migrator) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		_, db, err := bootstrap()
+		if err != nil {
+			return err
+		}
+		defer database.Close(db)
+
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			return cli.Exit("Error loading migrations: "+err.Error(), 1)
+		}
+		return action(c, migrator)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"project/internal/service"
+
+	"github.com/urfave/cli/v2"
+)
+
+func roleCommand() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{Name: "username", Usage: "username (email) of the user", Required: true},
+		&cli.StringFlag{Name: "role", Usage: "role name", Required: true},
+	}
+
+	return &cli.Command{
+		Name:  "role",
+		Usage: "Manage user roles",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "grant",
+				Usage: "Grant a role to a user",
+				Flags: flags,
+				Action: func(c *cli.Context) error {
+					return changeRole(c, service.RoleService.GrantRole, "Granted")
+				},
+			},
+			{
+				Name:  "revoke",
+				Usage: "Revoke a role from a user (the user's last role cannot be revoked)",
+				Flags: flags,
+				Action: func(c *cli.Context) error {
+					return changeRole(c, service.RoleService.RevokeRole, "Revoked")
+				},
+			},
+		},
+	}
+}
+
+// changeRole menjalankan grant/revoke role untuk user dari flag --username dan --role
+func changeRole(c *cli.Context, change func(service.RoleService, context.Context, string, string) error, verb string) error {
+	_, appContainer, err := bootstrapContainer(c.Context)
+	if err != nil {
+		return err
+	}
+	defer appContainer.Stop(c.Context)
+
+	userService, err := resolve[service.UserService](appContainer)
+	if err != nil {
+		return err
+	}
+	roleService, err := resolve[service.RoleService](appContainer)
+	if err != nil {
+		return err
+	}
+
+	user, err := findUser(c, userService)
+	if err != nil {
+		return err
+	}
+	if err := change(roleService, c.Context, user.ID.String(), c.String("role")); err != nil {
+		return cli.Exit(fmt.Sprintf("Error changing role: %v", err), 1)
+	}
+
+	fmt.Fprintf(c.App.Writer, "%s role %s for user %s\n", verb, c.String("role"), user.Username)
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"project/pkg/config"
+	"project/pkg/database"
+	"project/pkg/metrics"
+	"project/pkg/seeder"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/urfave/cli/v2"
+)
+
+func seedCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "seed",
+		Usage: "Seed roles, permissions and users from fixtures",
+		Description: "Fixtures are applied with upsert semantics, so seeding can be repeated safely.\n" +
+			"User passwords come from the env var named by password_env (e.g. SUPERADMIN_PASSWORD);\n" +
+			"when it is empty a random password is generated and printed once.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "env",
+				Usage: "environment whose seed sets are applied (default: app.env from config)",
+			},
+			&cli.StringFlag{
+				Name:  "fixtures",
+				Usage: "directory with .yaml/.json fixtures (default: built-in fixtures)",
+			},
+			&cli.StringFlag{
+				Name:    "pushgateway",
+				Usage:   "Prometheus Pushgateway URL to push app_seeder_duration_seconds to (job \"seeder\")",
+				EnvVars: []string{config.EnvPrefix + "_METRICS_PUSHGATEWAY"},
+			},
+		},
+		Action: func(c *cli.Context) error {
+			cfg, db, err := bootstrap()
+			if err != nil {
+				return err
+			}
+			defer database.Close(db)
+
+			env := c.String("env")
+			if env == "" {
+				env = cfg.App.Env
+			}
+
+			fixtures, err := seeder.DefaultFixtures()
+			if dir := c.String("fixtures"); dir != "" {
+				fixtures, err = seeder.LoadFixtures(os.DirFS(dir), ".")
+			}
+			if err != nil {
+				return cli.Exit("Error loading fixtures: "+err.Error(), 1)
+			}
+
+			// Proses seed tidak di-scrape lewat /metrics, jadi durasinya di-push
+			// ke Pushgateway jika dikonfigurasi
+			seedMetrics := metrics.New(prometheus.NewRegistry())
+			s := seeder.New(db, fixtures, c.App.Writer)
+			s.SetObserver(seedMetrics)
+			runErr := s.Run(c.Context, env)
+
+			if url := c.String("pushgateway"); url != "" {
+				if err := seedMetrics.Push(c.Context, url, "seeder"); err != nil {
+					fmt.Fprintf(c.App.ErrWriter, "Warning: pushing metrics to %s failed: %v\n", url, err)
+				}
+			}
+			if runErr != nil {
+				return cli.Exit("Error seeding database: "+runErr.Error(), 1)
+			}
+			return nil
+		},
+	}
+}
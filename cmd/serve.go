@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"os"
+	"os/signal"
+	"project/internal/container"
+	"project/internal/handler"
+	"project/internal/middleware"
+	"project/internal/modules"
+	"project/internal/routes"
+	"project/internal/utils/validator"
+	"project/pkg/config"
+	"project/pkg/database"
+	"project/pkg/health"
+	"project/pkg/logger"
+	"project/pkg/metrics"
+	"project/pkg/tracing"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	expvarmw "github.com/gofiber/fiber/v2/middleware/expvar"
+	fiberSwagger "github.com/swaggo/fiber-swagger"
+	"github.com/urfave/cli/v2"
+)
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "serve",
+		Usage:  "Run the HTTP API server",
+		Action: serve,
+	}
+}
+
+func serve(c *cli.Context) error {
+	cfg, db, err := bootstrap()
+	if err != nil {
+		return err
+	}
+
+	// Semua exit sebelum container di-Start menutup tracing dan database di sini.
+	// Setelah Start dipanggil, keduanya ditutup oleh hook OnStop container (juga
+	// saat Start gagal, karena hook dihentikan lagi)
+	var shutdownTracing func(context.Context) error
+	started := false
+	defer func() {
+		if started {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if shutdownTracing != nil {
+			shutdownTracing(ctx)
+		}
+		database.Close(db)
+	}()
+
+	// Tracing OpenTelemetry; span dikirim ke APM (OTLP) atau stdout sesuai logging.trace_exporter
+	shutdownTracing, err = tracing.Setup(context.Background(), tracing.Options{
+		Exporter:    cfg.Logging.TraceExporter,
+		Endpoint:    cfg.Logging.APMHost,
+		Insecure:    cfg.Logging.APMInsecure,
+		ServiceName: cfg.Logging.TraceServiceName,
+		SampleRatio: cfg.Logging.TraceSampleRatio,
+	})
+	if err != nil {
+		return cli.Exit("Error setting up tracing: "+err.Error(), 1)
+	}
+
+	// Metric Prometheus di /metrics (HTTP, auth, connection pool, durasi migrasi)
+	appMetrics := metrics.NewDefault()
+	if sqlDB, err := db.DB(); err == nil {
+		appMetrics.RegisterDB("primary", sqlDB)
+	}
+	if resolver := database.ResolverOf(db); resolver != nil {
+		for name, pool := range resolver.Pools() {
+			appMetrics.RegisterDB(name, pool)
+		}
+	}
+
+	// Container modul (core, roles, audit, users, auth). Hook lifecycle dijalankan
+	// sesuai urutan ditambahkan saat Start dan terbalik saat Stop: tracing di-flush
+	// dan database ditutup paling akhir
+	appContainer := container.New(modules.Default(cfg, db, appMetrics)...)
+	appContainer.Lifecycle().Append(container.Hook{Name: "tracing", OnStop: shutdownTracing})
+	appContainer.Lifecycle().Append(container.Hook{Name: "database", OnStop: func(context.Context) error {
+		return database.Close(db)
+	}})
+
+	// Migrate the database (nonaktifkan dengan database.migrate_on_start: false
+	// lalu jalankan manual: go run ./cmd migrate up)
+	if cfg.Database.MigrateOnStart {
+		if err := database.MigrateDatabase(db, appMetrics); err != nil {
+			return cli.Exit("Database migration failed: "+err.Error(), 1)
+		}
+	}
+
+	// Ping database secara berkala; status dan statistik pool dipublikasikan
+	// lewat expvar di /debug/vars
+	monitor, err := database.NewMonitor(db, cfg.Database.PingInterval)
+	if err != nil {
+		return cli.Exit("Error creating database monitor: "+err.Error(), 1)
+	}
+	appContainer.Lifecycle().Append(container.Hook{
+		Name:    "database monitor",
+		OnStart: func(context.Context) error { monitor.Start(context.Background()); return nil },
+		OnStop:  func(context.Context) error { monitor.Stop(); return nil },
+	})
+	expvar.Publish("database", expvar.Func(func() any { return monitor.Status() }))
+	expvar.Publish("logging", expvar.Func(func() any { return logger.Stats() }))
+
+	// Pemeriksaan dependency untuk /readyz; Elasticsearch hanya dilaporkan
+	// karena service tetap bisa melayani request tanpa pengiriman log
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return cli.Exit("Error loading migrations: "+err.Error(), 1)
+	}
+	healthRegistry := health.NewRegistry(cfg.App.HealthCheckTimeout)
+	healthRegistry.Register("database", health.DatabaseCheck(db))
+	healthRegistry.Register("migrations", health.MigrationsCheck(migrator))
+	if cfg.Logging.ELKHost != "" {
+		healthRegistry.RegisterOptional("elasticsearch", health.ElasticsearchCheck(nil, cfg.Logging.ELKHost))
+	}
+
+	// Rate limit per group route dan principal (lihat modules.Core)
+	limiter, err := container.Resolve[*middleware.RateLimiter](appContainer)
+	if err != nil {
+		return cli.Exit("Invalid rate limit config: "+err.Error(), 1)
+	}
+
+	// Hot reload: perubahan logging.* dan rate_limit.* di file config langsung
+	// berlaku; perubahan lain dicatat dan baru berlaku setelah restart
+	config.WatchConfig(cfg, func(next *config.Config) {
+		if err := logger.Setup(loggerOptions(next)); err != nil {
+			slog.Error("reloading logger failed", "error", err)
+		}
+		if err := limiter.Update(next); err != nil {
+			slog.Error("reloading rate limits failed", "error", err)
+		}
+	})
+
+	// Inisialisasi Fiber; semua error dari handler dan middleware ditulis sebagai
+	// application/problem+json (RFC 7807)
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+
+	// Request ID (X-Request-ID), span tracing dan metric untuk semua request, termasuk probe
+	app.Use(middleware.RequestID())
+	app.Use(middleware.Locale())
+	app.Use(middleware.Tracing())
+	app.Use(middleware.Metrics(appMetrics))
+
+	// Liveness dan readiness probe serta endpoint metrics, didaftarkan sebelum
+	// logger agar tidak memenuhi log
+	app.Get("/healthz", handler.Healthz)
+	app.Get("/readyz", handler.Readyz(healthRegistry))
+	app.Get("/metrics", adaptor.HTTPHandler(appMetrics.Handler()))
+
+	// Statistik runtime dan connection pool (JSON) di /debug/vars
+	app.Use(expvarmw.New())
+
+	// Initialize the validator
+	validator.InitValidator()
+
+	// Tambahkan middleware logger untuk mencatat semua request (JSON, komponen "http")
+	app.Use(middleware.RequestLogger())
+
+	// Route setiap modul di bawah /api; dependency dibuat oleh container
+	if err := routes.InitializeRoutes(app, appContainer); err != nil {
+		return cli.Exit("Error initializing routes: "+err.Error(), 1)
+	}
+
+	// Swagger route
+	app.Get("/swagger/*", fiberSwagger.WrapHandler)
+
+	// Jalankan worker background dan siapkan modul sebelum menerima request
+	// (jika gagal, hook yang sudah berjalan dihentikan lagi, termasuk database)
+	started = true
+	if err := appContainer.Start(context.Background()); err != nil {
+		return cli.Exit("Error starting application: "+err.Error(), 1)
+	}
+
+	// Start server; Listen kembali tanpa error setelah app.Shutdown dipanggil
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(":" + cfg.App.Port)
+	}()
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-listenErr:
+		stopContainer(appContainer)
+		return cli.Exit("Error starting server: "+err.Error(), 1)
+	case <-signalCtx.Done():
+	}
+	// Sinyal berikutnya menghentikan proses seketika (perilaku default)
+	stop()
+
+	shutdown(app, cfg.App.ShutdownDelay, cfg.App.ShutdownTimeout, healthRegistry, appContainer)
+	<-listenErr
+	return nil
+}
+
+// shutdown menghentikan server secara berurutan: readiness dibuat gagal, server
+// berhenti menerima koneksi dan menunggu request yang sedang berjalan, lalu
+// container dihentikan (worker background, connection pool database, span yang
+// tersisa dikirim ke exporter)
+func shutdown(app *fiber.App, delay, timeout time.Duration, healthRegistry *health.Registry, appContainer *container.Container) {
+	slog.Info("shutdown signal received, marking service as not ready", "delay", delay.String())
+	healthRegistry.SetShuttingDown()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	slog.Info("waiting for in-flight requests", "timeout", timeout.String())
+	if err := app.ShutdownWithTimeout(timeout); err != nil {
+		slog.Warn("shutdown timeout exceeded, remaining connections were closed", "error", err)
+	}
+
+	stopContainer(appContainer)
+	slog.Info("server stopped")
+}
+
+// stopContainer menjalankan hook OnStop container dengan batas waktu 5 detik
+func stopContainer(appContainer *container.Container) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := appContainer.Stop(ctx); err != nil {
+		slog.Warn("error stopping application", "error", err)
+	}
+}
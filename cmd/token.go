@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"project/internal/service"
+
+	"github.com/urfave/cli/v2"
+)
+
+func tokenCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "token",
+		Usage: "JWT helpers for debugging",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "mint",
+				Usage: "Print a signed JWT for a user without logging in",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "username", Usage: "username (email) of the user", Required: true},
+					&cli.DurationFlag{Name: "ttl", Usage: "token lifetime", Value: service.TokenTTL},
+				},
+				Action: func(c *cli.Context) error {
+					cfg, appContainer, err := bootstrapContainer(c.Context)
+					if err != nil {
+						return err
+					}
+					defer appContainer.Stop(c.Context)
+
+					userService, err := resolve[service.UserService](appContainer)
+					if err != nil {
+						return err
+					}
+					user, err := findUser(c, userService)
+					if err != nil {
+						return err
+					}
+
+					token, err := service.GenerateToken(cfg.App.JWTSecret, user, c.Duration("ttl"))
+					if err != nil {
+						return cli.Exit("Error generating token: "+err.Error(), 1)
+					}
+
+					fmt.Fprintln(c.App.Writer, token)
+					return nil
+				},
+			},
+		},
+	}
+}
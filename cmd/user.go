@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"project/internal/handler"
+	"project/internal/models"
+	"project/internal/service"
+	"project/internal/utils/validator"
+
+	"github.com/urfave/cli/v2"
+)
+
+func userCommand() *cli.Command {
+	usernameFlag := &cli.StringFlag{Name: "username", Usage: "username (email) of the user", Required: true}
+	passwordFlag := &cli.StringFlag{Name: "password", Usage: "password (min 8 characters with an uppercase letter, a lowercase letter and a digit)", Required: true}
+
+	return &cli.Command{
+		Name:  "user",
+		Usage: "Manage users",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "create",
+				Usage: "Create a user and attach its role",
+				Flags: []cli.Flag{
+					usernameFlag,
+					passwordFlag,
+					&cli.StringFlag{Name: "role", Usage: "role name (must already exist)", Required: true},
+				},
+				Action: withUserService(func(c *cli.Context, userService service.UserService) error {
+					// Aturan validasi yang sama dengan POST /api/users
+					req := handler.CreateUserRequest{
+						Username: c.String("username"),
+						Password: c.String("password"),
+						Role:     c.String("role"),
+					}
+					if err := validator.Validate(c.Context, &req); err != nil {
+						return cli.Exit("Invalid user: "+err.Error(), 2)
+					}
+
+					hashedPassword, err := service.HashPassword(req.Password)
+					if err != nil {
+						return err
+					}
+
+					user := models.User{Username: req.Username, Password: hashedPassword, Role: req.Role}
+					if err := userService.CreateUserWithRoles(c.Context, &user, []string{req.Role}); err != nil {
+						return cli.Exit("Error creating user: "+err.Error(), 1)
+					}
+
+					fmt.Fprintf(c.App.Writer, "Created user %s (%s)\n", user.Username, user.ID)
+					return nil
+				}),
+			},
+			{
+				Name:  "disable",
+				Usage: "Disable a user; login and tokens already issued are rejected",
+				Flags: []cli.Flag{usernameFlag},
+				Action: withUserService(func(c *cli.Context, userService service.UserService) error {
+					user, err := findUser(c, userService)
+					if err != nil {
+						return err
+					}
+					if err := userService.DisableUser(c.Context, user.ID.String()); err != nil {
+						return cli.Exit("Error disabling user: "+err.Error(), 1)
+					}
+
+					fmt.Fprintf(c.App.Writer, "Disabled user %s\n", user.Username)
+					return nil
+				}),
+			},
+			{
+				Name:  "reset-password",
+				Usage: "Set a new password for a user",
+				Flags: []cli.Flag{usernameFlag, passwordFlag},
+				Action: withUserService(func(c *cli.Context, userService service.UserService) error {
+					// Aturan password yang sama dengan PATCH /api/users/{id}
+					password := c.String("password")
+					if err := validator.Validate(c.Context, &handler.PatchUserRequest{Password: &password}); err != nil {
+						return cli.Exit("Invalid password: "+err.Error(), 2)
+					}
+
+					user, err := findUser(c, userService)
+					if err != nil {
+						return err
+					}
+					if err := userService.ResetPassword(c.Context, user.ID.String(), password); err != nil {
+						return cli.Exit("Error resetting password: "+err.Error(), 1)
+					}
+
+					fmt.Fprintf(c.App.Writer, "Password reset for user %s\n", user.Username)
+					return nil
+				}),
+			},
+		},
+	}
+}
+
+// withUserService menyiapkan UserService sebelum menjalankan action
+func withUserService(action func(c *cli.Context, userService service.UserService) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		validator.InitValidator()
+		_, appContainer, err := bootstrapContainer(c.Context)
+		if err != nil {
+			return err
+		}
+		defer appContainer.Stop(c.Context)
+
+		userService, err := resolve[service.UserService](appContainer)
+		if err != nil {
+			return err
+		}
+		return action(c, userService)
+	}
+}
+
+// findUser mencari user berdasarkan flag --username
+func findUser(c *cli.Context, userService service.UserService) (models.User, error) {
+	user, err := userService.GetUserByUsername(c.Context, c.String("username"))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return models.User{}, cli.Exit(fmt.Sprintf("User %q not found", c.String("username")), 1)
+		}
+		return models.User{}, err
+	}
+	return user, nil
+}
@@ -0,0 +1,137 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrNotProvided - Tidak ada provider untuk tipe yang diminta
+var ErrNotProvided = errors.New("no provider registered")
+
+// Container menyimpan provider dependency per tipe, modul yang terdaftar dan
+// lifecycle hook-nya. Dependency dibuat saat pertama kali di-Resolve (lazy) lalu
+// dipakai ulang (singleton), sehingga urutan modul tidak berpengaruh.
+// Container disusun saat aplikasi start dan tidak aman dipakai bersamaan dari
+// beberapa goroutine.
+type Container struct {
+	modules   []Module
+	providers map[reflect.Type]*provider
+	resolving []reflect.Type
+	lifecycle *Lifecycle
+}
+
+type provider struct {
+	construct func(*Container) (any, error)
+	value     any
+	resolved  bool
+}
+
+// New membuat container dan mendaftarkan provider dari setiap modul (lihat Module.Provide)
+func New(modules ...Module) *Container {
+	c := &Container{
+		modules:   modules,
+		providers: make(map[reflect.Type]*provider),
+		lifecycle: &Lifecycle{},
+	}
+	for _, module := range modules {
+		module.Provide(c)
+	}
+	return c
+}
+
+// Provide mendaftarkan constructor untuk tipe T. Provider yang sudah ada diganti,
+// berguna di test untuk menyuntikkan fake setelah New; mengganti tipe yang sudah
+// di-Resolve adalah kesalahan program (panic).
+func Provide[T any](c *Container, construct func(*Container) (T, error)) {
+	key := typeOf[T]()
+	if existing, ok := c.providers[key]; ok && existing.resolved {
+		panic(fmt.Sprintf("container: %s is already resolved and cannot be replaced", key))
+	}
+	c.providers[key] = &provider{construct: func(c *Container) (any, error) { return construct(c) }}
+}
+
+// Supply mendaftarkan value yang sudah jadi untuk tipe T (contoh config atau *gorm.DB)
+func Supply[T any](c *Container, value T) {
+	Provide(c, func(*Container) (T, error) { return value, nil })
+}
+
+// Resolve mengembalikan dependency bertipe T, membuatnya jika belum ada.
+// Dependency yang saling membutuhkan (siklus) dikembalikan sebagai error.
+func Resolve[T any](c *Container) (T, error) {
+	var zero T
+	key := typeOf[T]()
+	p, ok := c.providers[key]
+	if !ok {
+		return zero, fmt.Errorf("%w for %s", ErrNotProvided, key)
+	}
+	if !p.resolved {
+		for _, pending := range c.resolving {
+			if pending == key {
+				return zero, fmt.Errorf("dependency cycle: %s", c.cycle(key))
+			}
+		}
+		c.resolving = append(c.resolving, key)
+		value, err := p.construct(c)
+		c.resolving = c.resolving[:len(c.resolving)-1]
+		if err != nil {
+			return zero, fmt.Errorf("provide %s: %w", key, err)
+		}
+		p.value, p.resolved = value, true
+	}
+	return p.value.(T), nil
+}
+
+// MustResolve seperti Resolve tetapi panic jika gagal; untuk dependency yang
+// pasti tersedia, misalnya di dalam constructor yang dependency-nya sudah di-Resolve
+func MustResolve[T any](c *Container) T {
+	value, err := Resolve[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Lifecycle mengembalikan lifecycle container; provider menambahkan hook ke sini
+// untuk resource yang perlu dijalankan atau ditutup (worker, koneksi, buffer)
+func (c *Container) Lifecycle() *Lifecycle {
+	return c.lifecycle
+}
+
+// Mount mendaftarkan route setiap modul ke router, sesuai urutan modul di New
+func (c *Container) Mount(router fiber.Router) error {
+	for _, module := range c.modules {
+		if err := module.Routes(router, c); err != nil {
+			return fmt.Errorf("module %s: %w", module.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Start menjalankan hook OnStart (lihat Lifecycle.Start)
+func (c *Container) Start(ctx context.Context) error {
+	return c.lifecycle.Start(ctx)
+}
+
+// Stop menjalankan hook OnStop (lihat Lifecycle.Stop)
+func (c *Container) Stop(ctx context.Context) error {
+	return c.lifecycle.Stop(ctx)
+}
+
+// cycle menuliskan rantai dependency yang membentuk siklus, contoh "A -> B -> A"
+func (c *Container) cycle(key reflect.Type) string {
+	names := make([]string, 0, len(c.resolving)+1)
+	for _, pending := range c.resolving {
+		names = append(names, pending.String())
+	}
+	return strings.Join(append(names, key.String()), " -> ")
+}
+
+// typeOf mengembalikan reflect.Type untuk T, termasuk jika T adalah interface
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
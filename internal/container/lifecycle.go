@@ -0,0 +1,64 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Hook adalah pasangan fungsi start/stop untuk satu resource; keduanya boleh nil
+type Hook struct {
+	// Name dipakai di pesan error, contoh "database monitor"
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle menjalankan hook OnStart sesuai urutan ditambahkan dan OnStop dengan
+// urutan terbalik, sehingga resource yang dibuat belakangan ditutup lebih dulu
+type Lifecycle struct {
+	hooks   []Hook
+	started int
+	running bool
+}
+
+// Append menambahkan hook. Hook harus ditambahkan sebelum Start; menambahkan
+// hook saat lifecycle berjalan adalah kesalahan program (panic).
+func (l *Lifecycle) Append(hook Hook) {
+	if l.running {
+		panic(fmt.Sprintf("container: hook %q appended after start", hook.Name))
+	}
+	l.hooks = append(l.hooks, hook)
+}
+
+// Start menjalankan OnStart setiap hook. Jika satu hook gagal, hook yang sudah
+// berjalan dihentikan lagi (OnStop, urutan terbalik) dan error-nya dikembalikan.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.running = true
+	for _, hook := range l.hooks {
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				return errors.Join(fmt.Errorf("start %s: %w", hook.Name, err), l.Stop(ctx))
+			}
+		}
+		l.started++
+	}
+	return nil
+}
+
+// Stop menjalankan OnStop untuk hook yang sudah dijalankan Start, dengan urutan
+// terbalik. Semua hook tetap dihentikan meskipun ada yang gagal; error-nya digabung.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	var errs []error
+	for ; l.started > 0; l.started-- {
+		hook := l.hooks[l.started-1]
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+		}
+	}
+	l.running = false
+	return errors.Join(errs...)
+}
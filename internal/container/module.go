@@ -0,0 +1,16 @@
+package container
+
+import "github.com/gofiber/fiber/v2"
+
+// Module adalah satu fitur aplikasi (contoh users, auth, roles) yang mendaftarkan
+// dependency-nya sendiri dan route-nya. Modul baru cukup ditambahkan ke daftar
+// modul (lihat modules.Default) tanpa mengubah wiring di main.
+type Module interface {
+	// Name adalah nama modul untuk pesan error
+	Name() string
+	// Provide mendaftarkan constructor modul dengan container.Provide / container.Supply;
+	// dependency dari modul lain di-Resolve di dalam constructor, bukan di sini
+	Provide(c *Container)
+	// Routes mendaftarkan route modul ke router (group /api); modul tanpa route mengembalikan nil
+	Routes(router fiber.Router, c *Container) error
+}
@@ -0,0 +1,172 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldType - Pemetaan satu tipe field ke tipe Go dan tipe kolom per driver
+type fieldType struct {
+	GoType string
+	// Import adalah package yang dibutuhkan GoType, contoh "time"
+	Import   string
+	Postgres string
+	MySQL    string
+	SQLite   string
+	// Zero adalah default kolom untuk field opsional; kosong berarti kolom boleh NULL
+	// dan field model berupa pointer
+	Zero string
+	// Sample adalah contoh nilai Go untuk test yang di-generate
+	Sample string
+}
+
+// fieldTypes - Tipe yang didukung di spesifikasi field (name:type)
+var fieldTypes = map[string]fieldType{
+	"string":  {GoType: "string", Postgres: "text", MySQL: "varchar(255)", SQLite: "text", Zero: "''", Sample: `"sample"`},
+	"text":    {GoType: "string", Postgres: "text", MySQL: "text", SQLite: "text", Zero: "''", Sample: `"sample"`},
+	"int":     {GoType: "int", Postgres: "bigint", MySQL: "bigint", SQLite: "integer", Zero: "0", Sample: "1"},
+	"int64":   {GoType: "int64", Postgres: "bigint", MySQL: "bigint", SQLite: "integer", Zero: "0", Sample: "1"},
+	"float64": {GoType: "float64", Postgres: "double precision", MySQL: "double", SQLite: "real", Zero: "0", Sample: "1.5"},
+	"bool":    {GoType: "bool", Postgres: "boolean", MySQL: "boolean", SQLite: "boolean", Zero: "false", Sample: "true"},
+	"time":    {GoType: "time.Time", Import: "time", Postgres: "timestamptz", MySQL: "datetime(3)", SQLite: "datetime", Sample: "time.Now()"},
+	"uuid":    {GoType: "uuid.UUID", Import: "github.com/google/uuid", Postgres: "uuid", MySQL: "char(36)", SQLite: "text", Sample: "uuid.New()"},
+}
+
+// reservedFields - Kolom yang selalu dibuat generator
+var reservedFields = map[string]bool{"id": true, "version": true, "created_at": true, "updated_at": true}
+
+// Field adalah satu field resource dari spesifikasi name:type[:required][:unique]
+type Field struct {
+	fieldType
+	// Name adalah nama field Go, contoh "UnitPrice"
+	Name string
+	// Column adalah nama kolom dan nama JSON, contoh "unit_price"
+	Column   string
+	Type     string
+	Required bool
+	Unique   bool
+}
+
+// IsString melaporkan apakah field bertipe string (dikirim tanpa pointer di request)
+func (f Field) IsString() bool {
+	return f.GoType == "string"
+}
+
+// Nullable melaporkan apakah kolom boleh NULL (field opsional tanpa zero value di SQL)
+func (f Field) Nullable() bool {
+	return !f.Required && f.Zero == ""
+}
+
+// ModelType adalah tipe field di model; field nullable memakai pointer
+func (f Field) ModelType() string {
+	if f.Nullable() {
+		return "*" + f.GoType
+	}
+	return f.GoType
+}
+
+// RequestType adalah tipe field di request; selain string memakai pointer agar
+// field yang tidak dikirim bisa dibedakan dari zero value
+func (f Field) RequestType() string {
+	if f.IsString() {
+		return f.GoType
+	}
+	return "*" + f.GoType
+}
+
+// GormTag adalah isi tag gorm untuk field model
+func (f Field) GormTag() string {
+	var parts []string
+	if !f.Nullable() {
+		parts = append(parts, "not null")
+	}
+	if f.Unique {
+		parts = append(parts, "unique")
+	}
+	return strings.Join(parts, ";")
+}
+
+// ValidateTag adalah isi tag validate untuk field request
+func (f Field) ValidateTag() string {
+	switch {
+	case f.Required:
+		return "required"
+	case f.IsString():
+		return "omitempty"
+	}
+	return "omitnil"
+}
+
+// SQLType mengembalikan definisi kolom untuk driver (postgres, mysql, sqlite)
+func (f Field) SQLType(driver string) string {
+	column := map[string]string{"postgres": f.Postgres, "mysql": f.MySQL, "sqlite": f.SQLite}[driver]
+	switch {
+	case f.Required:
+		column += " NOT NULL"
+	case f.Zero != "" && driver == "mysql" && f.MySQL == "text":
+		// MySQL sebelum 8.0.13 tidak mengizinkan DEFAULT literal untuk kolom text;
+		// insert dari gorm selalu mengisi kolom ini
+		column += " NOT NULL"
+	case f.Zero != "":
+		column += " NOT NULL DEFAULT " + f.Zero
+	}
+	if f.Unique {
+		column += " UNIQUE"
+	}
+	return column
+}
+
+// ParseFields mem-parsing spesifikasi field, contoh "name:string:required:unique"
+// atau "price:float64". Tipe yang didukung: lihat FieldTypes.
+func ParseFields(specs []string) ([]Field, error) {
+	fields := make([]Field, 0, len(specs))
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("field %q: expected name:type[:required][:unique]", spec)
+		}
+
+		words := splitWords(parts[0])
+		if len(words) == 0 {
+			return nil, fmt.Errorf("field %q: name is empty", spec)
+		}
+		column := strings.Join(words, "_")
+		if reservedFields[column] {
+			return nil, fmt.Errorf("field %q: %s is always generated", spec, column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("field %q: duplicate field %s", spec, column)
+		}
+		seen[column] = true
+
+		typ, ok := fieldTypes[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("field %q: unknown type %q (supported: %s)", spec, parts[1], strings.Join(FieldTypes(), ", "))
+		}
+		field := Field{fieldType: typ, Name: goName(words), Column: column, Type: parts[1]}
+		for _, modifier := range parts[2:] {
+			switch modifier {
+			case "required":
+				field.Required = true
+			case "unique":
+				field.Unique = true
+			default:
+				return nil, fmt.Errorf("field %q: unknown modifier %q (supported: required, unique)", spec, modifier)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// FieldTypes mengembalikan nama tipe field yang didukung, terurut
+func FieldTypes() []string {
+	types := make([]string, 0, len(fieldTypes))
+	for name := range fieldTypes {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,262 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Template per file yang di-generate, mengikuti layout modul user
+//
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"backtick": func() string { return "`" },
+	// goZero mengubah default SQL field opsional menjadi zero value Go
+	"goZero": func(zero string) string {
+		if zero == "''" {
+			return `""`
+		}
+		return zero
+	},
+}).ParseFS(templateFiles, "templates/*.tmpl"))
+
+// ErrExists - File tujuan sudah ada dan Options.Force tidak diaktifkan
+var ErrExists = errors.New("file already exists")
+
+// Driver yang dibuatkan file migrasinya (lihat pkg/database/migrations)
+var migrationDrivers = []string{"postgres", "mysql", "sqlite"}
+
+// modulesFile adalah file berisi daftar modul (modules.Default)
+const modulesFile = "internal/modules/modules.go"
+
+// moduleMarker menandai posisi modul baru di modules.Default
+const moduleMarker = "// {generate}"
+
+// migrationFileName: <version>_<name>.<up|down>.sql, sama dengan pkg/database
+var migrationFileName = regexp.MustCompile(`^(\d+)_[a-zA-Z0-9_]+\.(up|down)\.sql$`)
+
+// Options mengatur modul yang di-generate
+type Options struct {
+	// Name adalah nama resource tunggal, contoh "product" atau "order_item"
+	Name string
+	// Plural menimpa bentuk jamak Name (nama tabel dan route); kosong berarti otomatis
+	Plural string
+	// Fields berisi spesifikasi field name:type[:required][:unique]
+	Fields []string
+	// Role adalah role yang boleh mengakses route modul
+	Role string
+	// Tests membuat test service dengan repository palsu (default di CLI)
+	Tests bool
+	// Force menimpa file yang sudah ada
+	Force bool
+	// Dir adalah root project (folder berisi go.mod)
+	Dir string
+}
+
+// data adalah isi yang dipakai template
+type data struct {
+	Names
+	Fields           []Field
+	Role             string
+	MigrationVersion string
+	// Imports berisi package tambahan untuk contoh nilai field di test, terurut
+	Imports []string
+}
+
+// Uses melaporkan apakah salah satu field membutuhkan package pkg
+func (d data) Uses(pkg string) bool {
+	for _, imported := range d.Imports {
+		if imported == pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// Result adalah hasil Generate
+type Result struct {
+	// Names adalah bentuk-bentuk nama resource, contoh Route untuk group rate limit
+	Names Names
+	// Files berisi path file yang ditulis atau diubah, relatif terhadap Options.Dir
+	Files []string
+}
+
+// file adalah satu file hasil render
+type file struct {
+	path    string
+	content []byte
+}
+
+// Generate membuat model, repository, service, handler, modul, migrasi (semua
+// driver) dan test (jika Options.Tests) untuk resource, lalu mendaftarkan modul di
+// modules.Default dan pesan di katalog i18n. Tidak ada file yang ditulis jika
+// salah satu file tujuan sudah ada (kecuali Options.Force).
+func Generate(opts Options) (Result, error) {
+	if len(splitWords(opts.Name)) == 0 {
+		return Result{}, errors.New("resource name is required")
+	}
+	fields, err := ParseFields(opts.Fields)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(fields) == 0 {
+		return Result{}, errors.New("at least one field is required")
+	}
+	if opts.Role == "" {
+		opts.Role = "superadmin"
+	}
+
+	names := newNames(opts.Name, opts.Plural)
+	version, err := migrationVersion(opts.Dir, names.Table)
+	if err != nil {
+		return Result{}, err
+	}
+	d := data{Names: names, Fields: fields, Role: opts.Role, MigrationVersion: fmt.Sprintf("%04d", version), Imports: imports(fields)}
+
+	plan := []struct{ template, path string }{
+		{"model.go.tmpl", "internal/models/" + names.Snake + ".go"},
+		{"repository.go.tmpl", "internal/repository/" + names.Snake + "_repository.go"},
+		{"service.go.tmpl", "internal/service/" + names.Snake + "_service.go"},
+		{"handler.go.tmpl", "internal/handler/" + names.Snake + "_handler.go"},
+		{"module.go.tmpl", "internal/modules/" + names.Table + ".go"},
+	}
+	if opts.Tests {
+		plan = append(plan, struct{ template, path string }{"service_test.go.tmpl", "internal/service/" + names.Snake + "_service_test.go"})
+	}
+	for _, driver := range migrationDrivers {
+		base := "pkg/database/migrations/" + driver + "/" + d.MigrationVersion + "_create_" + names.Table
+		plan = append(plan,
+			struct{ template, path string }{driver + ".up.sql.tmpl", base + ".up.sql"},
+			struct{ template, path string }{"down.sql.tmpl", base + ".down.sql"},
+		)
+	}
+
+	files := make([]file, 0, len(plan))
+	for _, item := range plan {
+		content, err := render(item.template, d)
+		if err != nil {
+			return Result{}, fmt.Errorf("render %s: %w", item.path, err)
+		}
+		if _, err := os.Stat(filepath.Join(opts.Dir, item.path)); err == nil && !opts.Force {
+			return Result{}, fmt.Errorf("%s: %w (use --force to overwrite)", item.path, ErrExists)
+		}
+		files = append(files, file{path: item.path, content: content})
+	}
+
+	result := Result{Names: names}
+	for _, f := range files {
+		target := filepath.Join(opts.Dir, f.path)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return result, err
+		}
+		if err := os.WriteFile(target, f.content, 0o644); err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, f.path)
+	}
+
+	changed, err := registerModule(opts.Dir, names)
+	if err != nil {
+		return result, err
+	}
+	if changed {
+		result.Files = append(result.Files, modulesFile)
+	}
+	changedCatalogs, err := addMessages(opts.Dir, names)
+	result.Files = append(result.Files, changedCatalogs...)
+	return result, err
+}
+
+// render mengeksekusi template; hasil Go diformat dengan gofmt
+func render(name string, d data) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, d); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return buf.Bytes(), nil
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// imports mengembalikan package tambahan yang dibutuhkan tipe field, terurut
+func imports(fields []Field) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, field := range fields {
+		if field.Import != "" && !seen[field.Import] {
+			seen[field.Import] = true
+			result = append(result, field.Import)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// migrationVersion mengembalikan versi migrasi create_<table> yang sudah ada (agar
+// --force menimpa file yang sama), atau versi berikutnya (tertinggi dari semua driver + 1)
+func migrationVersion(dir, table string) (int, error) {
+	highest, existing := 0, 0
+	for _, driver := range migrationDrivers {
+		entries, err := os.ReadDir(filepath.Join(dir, "pkg/database/migrations", driver))
+		if err != nil {
+			return 0, fmt.Errorf("read migrations (run the generator from the project root): %w", err)
+		}
+		for _, entry := range entries {
+			match := migrationFileName.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+			version, _ := strconv.Atoi(match[1])
+			if strings.HasPrefix(entry.Name(), match[1]+"_create_"+table+".") {
+				existing = version
+			}
+			if version > highest {
+				highest = version
+			}
+		}
+	}
+	if existing > 0 {
+		return existing, nil
+	}
+	return highest + 1, nil
+}
+
+// registerModule menambahkan modul baru di atas moduleMarker di modules.Default.
+// Mengembalikan false jika modul sudah terdaftar.
+func registerModule(dir string, names Names) (bool, error) {
+	path := filepath.Join(dir, modulesFile)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	entry := names.Plural + "(),"
+	if bytes.Contains(content, []byte("\t"+entry+"\n")) {
+		return false, nil
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		if strings.Contains(line, moduleMarker) {
+			indent := line[:len(line)-len(strings.TrimLeft(line, "\t"))]
+			lines = append(lines[:i], append([]string{indent + entry}, lines[i:]...)...)
+			return true, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644)
+		}
+	}
+	return false, fmt.Errorf("%s: marker %q not found, add %s to modules.Default manually", modulesFile, moduleMarker, entry)
+}
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// projectDirs adalah bagian project yang disalin untuk mengompilasi hasil generate
+var projectDirs = []string{"go.mod", "go.sum", "cmd", "docs", "internal", "pkg"}
+
+// copyProject menyalin project (root dari ../..) ke folder sementara
+func copyProject(t *testing.T) string {
+	t.Helper()
+	src, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := t.TempDir()
+	for _, name := range projectDirs {
+		err := filepath.WalkDir(filepath.Join(src, name), func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(src, path)
+			if entry.IsDir() {
+				return os.MkdirAll(filepath.Join(dst, rel), 0o755)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(dst, rel), content, 0o644)
+		})
+		if err != nil {
+			t.Fatalf("copy %s: %v", name, err)
+		}
+	}
+	return dst
+}
+
+func TestGenerateCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the whole project")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	dir := copyProject(t)
+	result, err := Generate(Options{
+		Name: "order_item",
+		Fields: []string{
+			"name:string:required:unique", "notes:text", "quantity:int:required", "total:int64",
+			"price:float64:required", "active:bool", "shipped_at:time", "warehouse_id:uuid:required",
+		},
+		Tests: true,
+		Dir:   dir,
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, want := range []string{"internal/handler/order_item_handler.go", modulesFile, "pkg/i18n/locales/en.json"} {
+		if !slices.Contains(result.Files, want) {
+			t.Errorf("Files = %v, want %s", result.Files, want)
+		}
+	}
+
+	// Generate kedua tanpa Force ditolak tanpa menulis apa pun
+	if _, err := Generate(Options{Name: "order_item", Fields: []string{"name:string"}, Dir: dir}); err == nil {
+		t.Error("second Generate without Force succeeded, want ErrExists")
+	}
+
+	// go vet mengompilasi package beserta test-nya, termasuk test service yang di-generate
+	cmd := exec.Command(goBin, "vet", "./cmd/...", "./internal/...", "./pkg/...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=readonly")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go vet on the generated module: %v\n%s", err, out)
+	}
+}
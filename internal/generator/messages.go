@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// catalogDir adalah folder katalog pesan i18n
+const catalogDir = "pkg/i18n/locales"
+
+// messages - Pesan baru per locale untuk resource; %s adalah Names.Label
+var messages = map[string][][2]string{
+	"en": {
+		{"error.%[1]s_not_found", "%[2]s not found"},
+		{"error.%[1]s_version_conflict", "%[2]s has been modified by another request"},
+		{"%[1]s.created", "%[2]s created successfully"},
+		{"%[1]s.updated", "%[2]s updated successfully"},
+	},
+	"id": {
+		{"error.%[1]s_not_found", "%[2]s tidak ditemukan"},
+		{"error.%[1]s_version_conflict", "%[2]s sudah diubah oleh permintaan lain"},
+		{"%[1]s.created", "%[2]s berhasil dibuat"},
+		{"%[1]s.updated", "%[2]s berhasil diperbarui"},
+	},
+}
+
+// addMessages menambahkan pesan resource ke akhir setiap katalog yang dikenal,
+// sebagai satu kelompok baru. Key yang sudah ada tidak diubah. Mengembalikan
+// katalog yang berubah.
+func addMessages(dir string, names Names) ([]string, error) {
+	var changed []string
+	for _, locale := range []string{"en", "id"} {
+		path := filepath.Join(catalogDir, locale+".json")
+		content, err := os.ReadFile(filepath.Join(dir, path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+
+		existing := make(map[string]string)
+		if err := json.Unmarshal(content, &existing); err != nil {
+			return changed, fmt.Errorf("%s: %w", path, err)
+		}
+
+		var lines []string
+		for _, message := range messages[locale] {
+			key := fmt.Sprintf(message[0], names.Snake)
+			if _, ok := existing[key]; ok {
+				continue
+			}
+			keyJSON, _ := json.Marshal(key)
+			valueJSON, _ := json.Marshal(fmt.Sprintf(message[1], names.Snake, names.Label))
+			lines = append(lines, "  "+string(keyJSON)+": "+string(valueJSON))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		// Sisipkan sebelum "}" penutup; entri terakhir yang ada diberi koma
+		end := bytes.LastIndexByte(content, '}')
+		body := strings.TrimRight(string(content[:end]), " \t\r\n")
+		separator := ",\n\n"
+		if strings.HasSuffix(body, "{") {
+			separator = "\n"
+		}
+		updated := body + separator + strings.Join(lines, ",\n") + "\n}\n"
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(updated), 0o644); err != nil {
+			return changed, err
+		}
+		changed = append(changed, path)
+	}
+	return changed, nil
+}
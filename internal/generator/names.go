@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms ditulis huruf besar semua di nama Go, contoh "sku" -> "SKU"
+var initialisms = map[string]bool{
+	"api": true, "http": true, "id": true, "ip": true, "json": true,
+	"sku": true, "sql": true, "url": true, "uuid": true,
+}
+
+// Names adalah bentuk-bentuk nama resource yang dipakai di template
+type Names struct {
+	// Type adalah nama tipe Go, contoh "OrderItem"
+	Type string
+	// Var adalah nama variabel, contoh "orderItem"
+	Var string
+	// Plural adalah nama jamak untuk nama fungsi, contoh "OrderItems"
+	Plural string
+	// PluralVar adalah nama jamak untuk variabel dan tipe modul, contoh "orderItems"
+	PluralVar string
+	// Snake adalah nama file dan prefix kode error, contoh "order_item"
+	Snake string
+	// Table adalah nama tabel dan file modul, contoh "order_items"
+	Table string
+	// Route adalah path route dan group rate limit, contoh "order-items"
+	Route string
+	// Label adalah nama untuk pesan, contoh "Order item"
+	Label string
+	// Lower adalah nama untuk pesan log, contoh "order item"
+	Lower string
+}
+
+// newNames membentuk Names dari nama resource (snake_case, kebab-case atau
+// CamelCase); plural boleh kosong untuk memakai bentuk jamak bahasa Inggris sederhana
+func newNames(name, plural string) Names {
+	words := splitWords(name)
+	pluralWords := splitWords(plural)
+	if len(pluralWords) == 0 {
+		pluralWords = append(append([]string{}, words[:len(words)-1]...), pluralize(words[len(words)-1]))
+	}
+
+	label := strings.Join(words, " ")
+	return Names{
+		Type:      goName(words),
+		Var:       varName(words),
+		Plural:    goName(pluralWords),
+		PluralVar: varName(pluralWords),
+		Snake:     strings.Join(words, "_"),
+		Table:     strings.Join(pluralWords, "_"),
+		Route:     strings.Join(pluralWords, "-"),
+		Label:     strings.ToUpper(label[:1]) + label[1:],
+		Lower:     label,
+	}
+}
+
+// splitWords memecah nama menjadi kata huruf kecil, contoh "OrderItem", "order_item"
+// dan "order-item" menjadi ["order", "item"]
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(strings.TrimSpace(name))
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			flush()
+			continue
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+func goName(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		if initialisms[word] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// varName membentuk nama camelCase; kata pertama tetap huruf kecil, termasuk initialism
+func varName(words []string) string {
+	return words[0] + goName(words[1:])
+}
+
+// pluralize membentuk jamak bahasa Inggris sederhana; pakai Options.Plural untuk kata tidak beraturan
+func pluralize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	}
+	return word + "s"
+}
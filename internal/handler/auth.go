@@ -1,17 +1,20 @@
 package handler
 
 import (
+	"errors"
 	"project/internal/service"
-	"time"
+	"project/internal/utils/validator"
+	"project/pkg/i18n"
+	"project/pkg/metrics"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type LoginRequest struct {
 	Username string `json:"username" validate:"required,email"` // Validasi email untuk username
-	Password string `json:"password" validate:"required,min=6"`
+	// Tanpa strong_password: user lama mungkin masih memakai password yang lebih lemah
+	Password string `json:"password" validate:"required"`
 	// Username string `json:"username"`
 	// Password string `json:"password"`
 }
@@ -21,11 +24,6 @@ type LoginResponse struct {
 	Token string `json:"token"`
 }
 
-// ErrorResponse mendeskripsikan struktur respons error
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
 // Define a custom response struct for the profile response
 type ProfileResponse struct {
 	Message string `json:"message"`
@@ -37,45 +35,58 @@ type ProfileResponse struct {
 // @Produce json
 // @Param loginRequest body LoginRequest true "Login Request"
 // @Success 200 {object} LoginResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /api/login [post]
-func Login(jwtSecret string, userService service.UserService) fiber.Handler {
+func Login(jwtSecret string, userService service.UserService, m *metrics.Metrics) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		var loginReq LoginRequest
-		if err := c.BodyParser(&loginReq); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
+		loginReq, err := validator.BindAndValidate[LoginRequest](c)
+		if err != nil {
+			m.LoginFailed("invalid_request")
+			return err
 		}
 
 		// Cek apakah user dengan username ini ada di database
-		user, err := userService.GetUserByUsername(loginReq.Username)
+		user, err := userService.GetUserByUsername(c.UserContext(), loginReq.Username)
+		if errors.Is(err, service.ErrUserNotFound) {
+			log.WarnContext(c.UserContext(), "login failed", "reason", "unknown_user")
+			m.LoginFailed("unknown_user")
+			return service.ErrInvalidCredentials
+		}
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid credentials"})
+			return err
 		}
 
 		// Verifikasi password dengan bcrypt
 		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginReq.Password)); err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid credentials"})
+			log.WarnContext(c.UserContext(), "login failed", "reason", "invalid_password", "user_id", user.ID)
+			m.LoginFailed("invalid_password")
+			return service.ErrInvalidCredentials
 		}
 
-		// Buat token JWT
-		token := jwt.New(jwt.SigningMethodHS256)
-		claims := token.Claims.(jwt.MapClaims)
-		claims["sub"] = user.ID
-		claims["username"] = user.Username
-		claims["role"] = user.Role
-		claims["permissions"] = user.Permissions
-		claims["exp"] = time.Now().Add(time.Hour * 72).Unix() // Token kadaluarsa dalam 72 jam
+		// User yang dinonaktifkan tidak boleh login
+		if user.DisabledAt != nil {
+			log.WarnContext(c.UserContext(), "login failed", "reason", "disabled", "user_id", user.ID)
+			m.LoginFailed("disabled")
+			return service.ErrUserDisabled
+		}
 
-		t, err := token.SignedString([]byte(jwtSecret))
+		// Buat token JWT, kadaluarsa dalam 72 jam
+		t, err := service.GenerateToken(jwtSecret, user, service.TokenTTL)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "could not generate token"})
+			m.LoginFailed("token_error")
+			return err
 		}
 
 		// Set role and permissions ke dalam context
 		c.Locals("userRole", user.Role)
 		c.Locals("userPermissions", user.Permissions)
 
+		log.InfoContext(c.UserContext(), "login succeeded", "user_id", user.ID)
+		m.LoginSucceeded()
 		return c.JSON(LoginResponse{Token: t})
 	}
 }
@@ -86,5 +97,5 @@ func Login(jwtSecret string, userService service.UserService) fiber.Handler {
 // @Success 200 {object} ProfileResponse
 // @Router /api/profile [get]
 func Profile(c *fiber.Ctx) error {
-	return c.JSON(ProfileResponse{Message: "Welcome to your profile!"})
+	return c.JSON(ProfileResponse{Message: i18n.T(c.UserContext(), "profile.welcome")})
 }
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project/internal/service"
+	"project/internal/utils/validator"
+	"project/pkg/i18n"
+	"project/pkg/logger"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationProblemJSON - Content-Type untuk problem details (RFC 7807)
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// problemTypePrefix adalah prefix URI type problem; diikuti code error
+const problemTypePrefix = "urn:problem:"
+
+// Problem - Respons error (RFC 7807 problem details).
+// Code stabil dan aman dipakai client untuk membedakan error; Detail untuk manusia.
+type Problem struct {
+	Type     string `json:"type" example:"urn:problem:user_not_found"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"User not found"`
+	Instance string `json:"instance,omitempty" example:"/api/users/6f1c8a7e-0b8e-4c39-9a43-0a0b8d1f6c11"`
+	Code     string `json:"code" example:"user_not_found"`
+	// RequestID sama dengan header X-Request-ID, untuk mencari log terkait
+	RequestID string `json:"request_id,omitempty"`
+	// Errors berisi pesan per field untuk error validasi
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// kindStatus memetakan jenis error service ke status HTTP
+var kindStatus = map[service.Kind]int{
+	service.KindInternal:           fiber.StatusInternalServerError,
+	service.KindValidation:         fiber.StatusBadRequest,
+	service.KindUnauthorized:       fiber.StatusUnauthorized,
+	service.KindForbidden:          fiber.StatusForbidden,
+	service.KindNotFound:           fiber.StatusNotFound,
+	service.KindConflict:           fiber.StatusConflict,
+	service.KindPreconditionFailed: fiber.StatusPreconditionFailed,
+	service.KindUnprocessable:      fiber.StatusUnprocessableEntity,
+	service.KindUnavailable:        fiber.StatusServiceUnavailable,
+}
+
+// ErrorHandler adalah error handler Fiber yang menulis semua error sebagai
+// application/problem+json. Handler dan middleware cukup mengembalikan error:
+//   - *service.Error memakai Kind, Code, Message dan Fields-nya
+//   - validator.FieldErrors menjadi 400 "validation_failed" dengan pesan per field
+//   - *fiber.Error memakai status dan pesannya, dengan code dari status (contoh "method_not_allowed")
+//   - context deadline dari middleware ContextTimeout menjadi 503
+//   - error lain menjadi 500 tanpa detail; error aslinya dicatat oleh RequestLogger
+//
+// Title, Detail dan pesan field diterjemahkan ke locale request (lihat middleware.Locale).
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	problem := ProblemFromError(err)
+	problem.Instance = c.OriginalURL()
+	problem.RequestID = logger.RequestID(c.UserContext())
+	localizeProblem(c, &problem)
+	return c.Status(problem.Status).JSON(problem, MIMEApplicationProblemJSON)
+}
+
+// localizeProblem menerjemahkan problem dengan katalog i18n: Title dari "status.<status>",
+// Detail dari "error.<code>" dan pesan field dari "field.<pesan>". Teks yang tidak
+// ada di katalog (contoh pesan validator yang sudah diterjemahkan) dibiarkan.
+func localizeProblem(c *fiber.Ctx, problem *Problem) {
+	locale := i18n.FromContext(c.UserContext())
+	if key := "status." + strconv.Itoa(problem.Status); i18n.Has(key) {
+		problem.Title = i18n.Translate(locale, key)
+	}
+	if key := "error." + problem.Code; i18n.Has(key) {
+		problem.Detail = i18n.Translate(locale, key, "retry_after", c.GetRespHeader(fiber.HeaderRetryAfter))
+	}
+
+	// Fields bisa milik error sentinel, jadi hasil terjemahan ditulis ke map baru
+	if len(problem.Errors) > 0 {
+		fields := make(map[string]string, len(problem.Errors))
+		for field, message := range problem.Errors {
+			if key := "field." + message; i18n.Has(key) {
+				message = i18n.Translate(locale, key, "field", field)
+			}
+			fields[field] = message
+		}
+		problem.Errors = fields
+	}
+}
+
+// ProblemFromError membentuk Problem (tanpa Instance, RequestID dan terjemahan) dari err
+func ProblemFromError(err error) Problem {
+	var appErr *service.Error
+	var fieldErrs validator.FieldErrors
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &appErr):
+		status, ok := kindStatus[appErr.Kind]
+		if !ok {
+			status = fiber.StatusInternalServerError
+		}
+		if status == fiber.StatusInternalServerError {
+			return newProblem(status, "internal_error", "An unexpected error occurred")
+		}
+		problem := newProblem(status, appErr.Code, appErr.Message)
+		problem.Errors = appErr.Fields
+		return problem
+	case errors.As(err, &fieldErrs):
+		problem := newProblem(fiber.StatusBadRequest, "validation_failed", "Validation errors occurred")
+		problem.Errors = fieldErrs
+		return problem
+	case errors.Is(err, validator.ErrInvalidBody):
+		return ProblemFromError(errInvalidPayload)
+	case errors.As(err, &fiberErr):
+		code := statusCode(fiberErr.Code)
+		if fiberErr.Code >= fiber.StatusInternalServerError {
+			return newProblem(fiberErr.Code, code, http.StatusText(fiberErr.Code))
+		}
+		return newProblem(fiberErr.Code, code, fiberErr.Message)
+	case errors.Is(err, context.DeadlineExceeded):
+		return newProblem(fiber.StatusServiceUnavailable, "timeout", "The request took too long to process")
+	}
+	return newProblem(fiber.StatusInternalServerError, "internal_error", "An unexpected error occurred")
+}
+
+func newProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   problemTypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// statusCode membentuk code dari teks status HTTP, contoh 405 -> "method_not_allowed"
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
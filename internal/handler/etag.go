@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"project/internal/models"
+	"project/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	// errPreconditionRequired - If-Match wajib dikirim (app.require_if_match) tetapi kosong (428)
+	errPreconditionRequired = fiber.NewError(fiber.StatusPreconditionRequired, "If-Match header is required")
+	// errPreconditionFailed - ETag pada If-Match tidak cocok dengan versi saat ini
+	errPreconditionFailed = &service.Error{Kind: service.KindPreconditionFailed, Code: "precondition_failed", Message: "User has been modified by another request"}
+)
+
+// userETag membentuk ETag (strong) dari versi user
+func userETag(user models.User) string {
+	return `"` + strconv.FormatUint(uint64(user.Version), 10) + `"`
+}
+
+// parseIfMatch mem-parsing header If-Match.
+// wildcard bernilai true untuk "*"; versions berisi versi dari setiap ETag strong.
+// ETag weak (W/"...") diabaikan karena If-Match memakai strong comparison.
+func parseIfMatch(header string) (versions []uint, wildcard bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		version, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 0)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, uint(version))
+	}
+	return versions, false
+}
+
+// ifMatchVersion mengevaluasi header If-Match untuk user dengan ID tertentu dan
+// mengembalikan versi yang diharapkan; 0 berarti tanpa prasyarat versi.
+func (h *UserHandler) ifMatchVersion(c *fiber.Ctx, userID string) (uint, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		if h.requireIfMatch {
+			return 0, errPreconditionRequired
+		}
+		return 0, nil
+	}
+
+	versions, wildcard := parseIfMatch(header)
+	switch {
+	case wildcard:
+		return 0, nil
+	case len(versions) == 0:
+		return 0, errPreconditionFailed
+	case len(versions) == 1:
+		// Dicek secara atomik oleh service/repository
+		return versions[0], nil
+	}
+
+	// Daftar ETag: pilih yang cocok dengan versi saat ini. Error lain (user tidak
+	// ada, database tidak tersedia) dikembalikan apa adanya, bukan sebagai 412
+	current, err := h.userService.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		return 0, err
+	}
+	for _, version := range versions {
+		if version == current.Version {
+			return version, nil
+		}
+	}
+	return 0, errPreconditionFailed
+}
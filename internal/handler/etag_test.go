@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header       string
+		wantVersions []uint
+		wantWildcard bool
+	}{
+		{`"3"`, []uint{3}, false},
+		{`"3", "5"`, []uint{3, 5}, false},
+		{` "3" ,"5" `, []uint{3, 5}, false},
+		{`*`, nil, true},
+		{`"3", *`, nil, true},
+		// ETag weak dan yang tidak berisi versi diabaikan
+		{`W/"3"`, nil, false},
+		{`W/"3", "4"`, []uint{4}, false},
+		{`3`, nil, false},
+		{`"abc"`, nil, false},
+		{`"-1"`, nil, false},
+		{`""`, nil, false},
+	}
+	for _, tt := range tests {
+		versions, wildcard := parseIfMatch(tt.header)
+		if !reflect.DeepEqual(versions, tt.wantVersions) || wildcard != tt.wantWildcard {
+			t.Errorf("parseIfMatch(%q) = %v, %v; want %v, %v", tt.header, versions, wildcard, tt.wantVersions, tt.wantWildcard)
+		}
+	}
+}
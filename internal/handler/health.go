@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"project/pkg/health"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthResponse mendeskripsikan respons liveness
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// @Summary Liveness probe
+// @Description Returns 200 while the process is able to serve requests. Dependencies are not checked.
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthResponse
+// @Router /healthz [get]
+func Healthz(c *fiber.Ctx) error {
+	return c.JSON(HealthResponse{Status: health.StatusOK})
+}
+
+// @Summary Readiness probe
+// @Description Runs every registered dependency check (database ping, pending migrations, optional Elasticsearch) and reports the status and latency of each. Returns 503 when a critical check fails.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func Readyz(registry *health.Registry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report := registry.Run(c.UserContext())
+		if !report.Healthy() {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+		}
+		return c.JSON(report)
+	}
+}
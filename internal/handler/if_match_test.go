@@ -0,0 +1,116 @@
+package handler_test
+
+import (
+	"context"
+	"project/internal/handler"
+	"project/internal/models"
+	"project/internal/service"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func TestUserETagAndIfMatch(t *testing.T) {
+	a := newUserTestApp(t, false)
+	user := a.createUser(t, "etag@mail.com")
+	path := "/api/users/" + user.ID.String()
+
+	resp, body := a.do(t, fiber.MethodGet, path, "")
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderETag) != `"1"` {
+		t.Fatalf("GET = %d ETag %q %s, want 200 with ETag \"1\"", resp.StatusCode, resp.Header.Get(fiber.HeaderETag), body)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		ifMatch  string
+		want     int
+		wantCode string
+		wantETag string
+	}{
+		{"put with current etag", fiber.MethodPut, `{"username":"etag@mail.com","role":"admin"}`, `"1"`, fiber.StatusOK, "", `"2"`},
+		{"patch with stale etag", fiber.MethodPatch, `{"role":"viewer"}`, `"1"`, fiber.StatusPreconditionFailed, "version_conflict", ""},
+		{"put with stale etag", fiber.MethodPut, `{"username":"etag@mail.com","role":"viewer"}`, `"1"`, fiber.StatusPreconditionFailed, "precondition_failed", ""},
+		{"weak etag only", fiber.MethodPatch, `{"role":"viewer"}`, `W/"2"`, fiber.StatusPreconditionFailed, "precondition_failed", ""},
+		{"list without current version", fiber.MethodPatch, `{"role":"viewer"}`, `"7", "8"`, fiber.StatusPreconditionFailed, "precondition_failed", ""},
+		{"list with current version", fiber.MethodPatch, `{"role":"viewer"}`, `"7", "2"`, fiber.StatusOK, "", `"3"`},
+		{"wildcard", fiber.MethodPatch, `{"role":"admin"}`, `*`, fiber.StatusOK, "", `"4"`},
+		{"without if-match", fiber.MethodPatch, `{"role":"viewer"}`, "", fiber.StatusOK, "", `"5"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []string
+			if tt.ifMatch != "" {
+				headers = []string{fiber.HeaderIfMatch, tt.ifMatch}
+			}
+			resp, body := a.do(t, tt.method, path, tt.body, headers...)
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d %s, want %d", resp.StatusCode, body, tt.want)
+			}
+			if tt.wantCode != "" {
+				if p := problem(t, body); p.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", p.Code, tt.wantCode)
+				}
+			}
+			if etag := resp.Header.Get(fiber.HeaderETag); etag != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", etag, tt.wantETag)
+			}
+		})
+	}
+}
+
+func TestIfMatchRequired(t *testing.T) {
+	a := newUserTestApp(t, true)
+	user := a.createUser(t, "required@mail.com")
+	path := "/api/users/" + user.ID.String()
+
+	for _, method := range []string{fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete} {
+		body := ""
+		if method != fiber.MethodDelete {
+			body = `{"username":"required@mail.com","role":"admin"}`
+		}
+		resp, data := a.do(t, method, path, body)
+		if resp.StatusCode != fiber.StatusPreconditionRequired {
+			t.Errorf("%s without If-Match = %d %s, want 428", method, resp.StatusCode, data)
+		}
+	}
+
+	resp, data := a.do(t, fiber.MethodDelete, path, "", fiber.HeaderIfMatch, `"1"`)
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Errorf("DELETE with If-Match = %d %s, want 204", resp.StatusCode, data)
+	}
+}
+
+// stubUserService mengembalikan err dari GetUserByID; method lain tidak dipakai
+type stubUserService struct {
+	service.UserService
+	err error
+}
+
+func (s stubUserService) GetUserByID(context.Context, string) (models.User, error) {
+	return models.User{}, s.err
+}
+
+func TestIfMatchListPassesLookupErrors(t *testing.T) {
+	unavailable := &service.Error{Kind: service.KindUnavailable, Code: "service_unavailable", Message: "Database is unavailable"}
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{service.ErrUserNotFound, fiber.StatusNotFound},
+		{unavailable, fiber.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		userHandler := handler.NewUserHandler(stubUserService{err: tt.err}, false)
+		app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+		app.Delete("/api/users/:id", userHandler.DeleteUser)
+		a := &userTestApp{app: app}
+
+		resp, body := a.do(t, fiber.MethodDelete, "/api/users/"+uuid.NewString(), "", fiber.HeaderIfMatch, `"1", "2"`)
+		if resp.StatusCode != tt.want {
+			t.Errorf("DELETE with If-Match list and %v = %d %s, want %d", tt.err, resp.StatusCode, body, tt.want)
+		}
+	}
+}
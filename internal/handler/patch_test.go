@@ -0,0 +1,69 @@
+package handler_test
+
+import (
+	"context"
+	"project/internal/handler"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestPatchUserMergePatch(t *testing.T) {
+	a := newUserTestApp(t, false)
+	user := a.createUser(t, "patch@mail.com")
+	path := "/api/users/" + user.ID.String()
+	mergePatch := []string{fiber.HeaderContentType, handler.MIMEApplicationMergePatchJSON}
+
+	tests := []struct {
+		name       string
+		body       string
+		headers    []string
+		want       int
+		wantCode   string
+		wantFields []string
+	}{
+		{"null is rejected", `{"role":null}`, mergePatch, fiber.StatusBadRequest, "validation_failed", []string{"role"}},
+		{"non-patchable fields", `{"id":"x","version":9,"disabled_at":"2024-01-01T00:00:00Z"}`, mergePatch, fiber.StatusBadRequest, "validation_failed", []string{"id", "version", "disabled_at"}},
+		{"errors are collected", `{"created_at":null,"username":"not-an-email"}`, mergePatch, fiber.StatusBadRequest, "validation_failed", []string{"created_at", "username"}},
+		// Nilai kosong dikirim sebagai nilai, bukan dianggap field yang tidak dikirim
+		{"empty role", `{"role":""}`, mergePatch, fiber.StatusBadRequest, "validation_failed", []string{"role"}},
+		{"empty locale", `{"locale":""}`, mergePatch, fiber.StatusBadRequest, "validation_failed", []string{"locale"}},
+		{"empty username", `{"username":""}`, mergePatch, fiber.StatusBadRequest, "validation_failed", []string{"username"}},
+		{"not an object", `["role"]`, mergePatch, fiber.StatusBadRequest, "invalid_payload", nil},
+		{"unsupported content type", `{"role":"admin"}`, []string{fiber.HeaderContentType, fiber.MIMETextPlain}, fiber.StatusUnsupportedMediaType, "unsupported_media_type", nil},
+		{"empty document", `{}`, mergePatch, fiber.StatusOK, "", nil},
+		{"application/json", `{"locale":"id"}`, nil, fiber.StatusOK, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := a.do(t, fiber.MethodPatch, path, tt.body, tt.headers...)
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d %s, want %d", resp.StatusCode, body, tt.want)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			p := problem(t, body)
+			if p.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", p.Code, tt.wantCode)
+			}
+			if len(p.Errors) != len(tt.wantFields) {
+				t.Errorf("errors = %v, want fields %v", p.Errors, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if p.Errors[field] == "" {
+					t.Errorf("errors = %v, want an error for %s", p.Errors, field)
+				}
+			}
+		})
+	}
+
+	// Hanya locale yang berubah; request yang ditolak tidak mengubah apa pun
+	got, err := a.service.GetUserByID(context.Background(), user.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if got.Username != "patch@mail.com" || got.Role != "viewer" || got.Locale != "id" || got.Password != user.Password {
+		t.Errorf("user = %s %s %q, want patch@mail.com viewer \"id\" with the same password", got.Username, got.Role, got.Locale)
+	}
+}
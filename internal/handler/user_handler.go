@@ -1,26 +1,33 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"project/internal/models"
 	"project/internal/service"
-
-	myValidator "project/internal/utils/validator"
-
-	"github.com/go-playground/validator/v10"
+	"project/internal/utils/validator"
+	"project/pkg/i18n"
+	"project/pkg/logger"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
+// log adalah logger komponen "handler"
+var log = logger.New("handler")
+
 // UserHandler - Struct untuk handler user
 type UserHandler struct {
-	userService service.UserService
+	userService    service.UserService
+	requireIfMatch bool
 }
 
-// NewUserHandler - Fungsi untuk membuat instance baru dari UserHandler
-func NewUserHandler(userService service.UserService) *UserHandler {
-	return &UserHandler{userService}
+// NewUserHandler - Fungsi untuk membuat instance baru dari UserHandler.
+// requireIfMatch mewajibkan header If-Match pada PUT/PATCH/DELETE.
+func NewUserHandler(userService service.UserService, requireIfMatch bool) *UserHandler {
+	return &UserHandler{userService, requireIfMatch}
 }
 
 // GetAllUsersResponse - Struct untuk response GetAllUsers
@@ -41,10 +48,6 @@ type UpdateUserResponse struct {
 	Data    models.User `json:"data"`
 }
 
-// type ErrorResponse struct {
-// 	Error string `json:"error"`
-// }
-
 // @Summary Get all users
 // @Description Retrieve a list of users with pagination, filtering, and sorting
 // @Produce json
@@ -54,7 +57,9 @@ type UpdateUserResponse struct {
 // @Param sort query string false "Sorting criteria" default("created_at desc")
 // @Param username query string false "Filter by username"
 // @Success 200 {object} GetAllUsersResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 500 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /api/users [get]
 func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
 	page := c.QueryInt("page", 1)              // Default page 1
@@ -69,9 +74,9 @@ func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
 	}
 
 	// Call service to get users data
-	users, total, err := h.userService.GetAllUsers(page, limit, sort, filter)
+	users, total, err := h.userService.GetAllUsers(c.UserContext(), page, limit, sort, filter)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get users"})
+		return err
 	}
 
 	// Respond with user data and pagination info
@@ -90,29 +95,35 @@ func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "User ID"
 // @Success 200 {object} models.User
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Header 200 {string} ETag "Current version of the user"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /api/users/{id} [get]
 func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if _, err := uuid.Parse(id); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ID format"})
+		return errInvalidID
 	}
 
 	// Call service to get user by ID
-	user, err := h.userService.GetUserByID(id)
+	user, err := h.userService.GetUserByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		return err
 	}
 
+	c.Set(fiber.HeaderETag, userETag(user))
 	return c.JSON(user)
 }
 
 // CreateUserRequest - Request body structure for creating a new user
 type CreateUserRequest struct {
-	Username string `json:"username" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
-	Role     string `json:"role" validate:"required"`
+	Username string `json:"username" validate:"required,email,unique_username"`
+	Password string `json:"password" validate:"required,strong_password" minLength:"8"`
+	Role     string `json:"role" validate:"required,role_exists"`
+	// Locale - Bahasa respons yang dipilih user (en, id); kosong berarti mengikuti Accept-Language
+	Locale string `json:"locale,omitempty" validate:"omitempty,supported_locale" enums:"en,id"`
 }
 
 // @Summary Create a new user
@@ -122,54 +133,23 @@ type CreateUserRequest struct {
 // @Security BearerAuth
 // @Param createUserRequest body CreateUserRequest true "Create User Request"
 // @Success 201 {object} handler.CreateUserResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /api/users [post]
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
-	var req CreateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request payload",
-		})
-	}
-
-	// Validate the request using a custom validator
-	if err := myValidator.ValidateStruct(&req); err != nil {
-		var errorMessage string
-		var errorDetails = make(map[string]string)
-
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			for _, fieldErr := range validationErrors {
-				switch fieldErr.Field() {
-				case "Username":
-					errorDetails["username"] = "Username must be a valid email"
-				case "Password":
-					errorDetails["password"] = "Password must be at least 6 characters"
-				case "Role":
-					errorDetails["role"] = "Role is required"
-				default:
-					errorDetails[fieldErr.Field()] = "Invalid input"
-				}
-			}
-			errorMessage = "Validation errors occurred"
-		} else {
-			errorMessage = "An unknown validation error occurred"
-		}
-
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"message": errorMessage,
-			"errors":  errorDetails,
-		})
+	req, err := validator.BindAndValidate[CreateUserRequest](c)
+	if err != nil {
+		return err
 	}
 
 	// Hash the password
 	hashedPassword, err := service.HashPassword(req.Password)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to hash password",
-		})
+		return err
 	}
 
 	// Create a new user
@@ -177,26 +157,27 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 		Username: req.Username,
 		Password: hashedPassword,
 		Role:     req.Role,
+		Locale:   req.Locale,
 	}
 
 	// Call the service to create the user
-	if err := h.userService.CreateUser(&newUser); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create user",
-		})
+	if err := h.userService.CreateUser(c.UserContext(), &newUser); err != nil {
+		return err
 	}
 
+	c.Set(fiber.HeaderETag, userETag(newUser))
 	return c.Status(http.StatusCreated).JSON(map[string]interface{}{
-		"message": "User created successfully",
+		"message": i18n.T(c.UserContext(), "user.created"),
 		"data":    newUser,
 	})
 }
 
 // EditUserRequest - Request body structure for editing a user
 type EditUserRequest struct {
-	Username string `json:"username" validate:"required"`
-	Password string `json:"password,omitempty" validate:"min=6"`
-	Role     string `json:"role" validate:"required"`
+	Username string `json:"username" validate:"required,email"`
+	Password string `json:"password,omitempty" validate:"omitempty,strong_password" minLength:"8"`
+	Role     string `json:"role" validate:"required,role_exists"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,supported_locale" enums:"en,id"`
 }
 
 // @Summary Update an existing user
@@ -205,36 +186,43 @@ type EditUserRequest struct {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID"
+// @Param If-Match header string false "ETag from GET /api/users/{id}"
 // @Param editUserRequest body EditUserRequest true "Edit User Request"
 // @Success 200 {object} UpdateUserResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 428 {object} Problem
+// @Failure 500 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /api/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	userID := c.Params("id")
-
-	var req EditUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request payload",
-		})
+	if _, err := uuid.Parse(userID); err != nil {
+		return errInvalidID
 	}
 
-	// Validate the request
-	if err := myValidator.ValidateStruct(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Validation failed",
-			"details": err.Error(),
-		})
+	req, err := validator.BindAndValidate[EditUserRequest](c)
+	if err != nil {
+		return err
 	}
 
 	// Fetch the existing user
-	existingUser, err := h.userService.GetUserByID(userID)
+	existingUser, err := h.userService.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Check If-Match against the version we just read
+	version, err := h.ifMatchVersion(c, userID)
 	if err != nil {
-		return c.Status(http.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		return err
+	}
+	if version != 0 && version != existingUser.Version {
+		return errPreconditionFailed
 	}
 
 	// Update fields
@@ -246,48 +234,184 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 		existingUser.Role = req.Role
 	}
 
+	if req.Locale != "" {
+		existingUser.Locale = req.Locale
+	}
+
 	// Hash password if provided
 	if req.Password != "" {
 		hashedPassword, err := service.HashPassword(req.Password)
 		if err != nil {
-			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to hash password",
-			})
+			return err
 		}
 		existingUser.Password = hashedPassword
 	}
 
 	// Call service to update user
-	if err := h.userService.UpdateUser(userID, &existingUser); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update user",
-		})
+	if err := h.userService.UpdateUser(c.UserContext(), userID, &existingUser); err != nil {
+		return err
 	}
 
+	c.Set(fiber.HeaderETag, userETag(existingUser))
 	return c.Status(http.StatusOK).JSON(map[string]interface{}{
-		"message": "User updated successfully",
+		"message": i18n.T(c.UserContext(), "user.updated"),
 		"data":    existingUser,
 	})
 }
 
+// MIMEApplicationMergePatchJSON - Content-Type untuk JSON Merge Patch (RFC 7396)
+const MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+
+// PatchUserRequest - Dokumen JSON Merge Patch (RFC 7396) untuk user.
+// Field yang tidak dikirim tidak diubah; nilai null ditolak karena semua kolom wajib ada.
+type PatchUserRequest struct {
+	Username *string `json:"username,omitempty" validate:"omitnil,email"`
+	Password *string `json:"password,omitempty" validate:"omitnil,strong_password" minLength:"8"`
+	Role     *string `json:"role,omitempty" validate:"omitnil,min=1,role_exists"`
+	Locale   *string `json:"locale,omitempty" validate:"omitnil,supported_locale" enums:"en,id"`
+}
+
+// patchableUserFields - Field yang boleh diubah lewat PATCH
+var patchableUserFields = map[string]bool{
+	"username": true,
+	"password": true,
+	"role":     true,
+	"locale":   true,
+}
+
+// @Summary Partially update a user
+// @Description Apply a JSON Merge Patch (RFC 7396) to a user. Only the provided fields are changed.
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param If-Match header string false "ETag from GET /api/users/{id}"
+// @Param patchUserRequest body PatchUserRequest true "Patch User Request"
+// @Success 200 {object} UpdateUserResponse
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 415 {object} Problem
+// @Failure 428 {object} Problem
+// @Failure 500 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /api/users/{id} [patch]
+func (h *UserHandler) PatchUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		return errInvalidID
+	}
+
+	// Hanya menerima application/merge-patch+json atau application/json
+	contentType := strings.ToLower(c.Get(fiber.HeaderContentType))
+	if !strings.HasPrefix(contentType, MIMEApplicationMergePatchJSON) && !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json or application/json")
+	}
+
+	// Dokumen patch harus berupa JSON object
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &doc); err != nil || doc == nil {
+		return errInvalidPayload
+	}
+
+	errorDetails := make(validator.FieldErrors)
+	for field, raw := range doc {
+		if !patchableUserFields[field] {
+			errorDetails[field] = i18n.T(c.UserContext(), "field.not_patchable", "field", field)
+			continue
+		}
+		if string(raw) == "null" {
+			errorDetails[field] = i18n.T(c.UserContext(), "field.not_removable", "field", field)
+		}
+	}
+
+	var req PatchUserRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return errInvalidPayload
+	}
+
+	var fieldErrs validator.FieldErrors
+	if err := validator.Validate(c.UserContext(), &req); errors.As(err, &fieldErrs) {
+		for field, message := range fieldErrs {
+			errorDetails[field] = message
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if len(errorDetails) > 0 {
+		return errorDetails
+	}
+
+	// Kumpulkan hanya kolom yang dikirim
+	fields := make(map[string]interface{})
+	if req.Username != nil {
+		fields["username"] = *req.Username
+	}
+	if req.Role != nil {
+		fields["role"] = *req.Role
+	}
+	if req.Locale != nil {
+		fields["locale"] = *req.Locale
+	}
+	if req.Password != nil {
+		hashedPassword, err := service.HashPassword(*req.Password)
+		if err != nil {
+			return err
+		}
+		fields["password"] = hashedPassword
+	}
+
+	version, err := h.ifMatchVersion(c, userID)
+	if err != nil {
+		return err
+	}
+
+	updatedUser, err := h.userService.PatchUser(c.UserContext(), userID, version, fields)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, userETag(updatedUser))
+	return c.Status(http.StatusOK).JSON(UpdateUserResponse{
+		Message: i18n.T(c.UserContext(), "user.updated"),
+		Data:    updatedUser,
+	})
+}
+
 // @Summary Delete a user
 // @Description Delete a user by their ID
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID"
+// @Param If-Match header string false "ETag from GET /api/users/{id}"
 // @Success 204 {object} nil
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 428 {object} Problem
+// @Failure 500 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /api/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	userID := c.Params("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		return errInvalidID
+	}
+
+	version, err := h.ifMatchVersion(c, userID)
+	if err != nil {
+		return err
+	}
 
 	// Call service to delete user
-	if err := h.userService.DeleteUser(userID); err != nil {
-		if err == service.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "User not found"})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to delete user"})
+	if err := h.userService.DeleteUser(c.UserContext(), userID, version); err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusNoContent).JSON(nil)
@@ -0,0 +1,128 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/handler"
+	"project/internal/middleware"
+	"project/internal/models"
+	"project/internal/repository"
+	"project/internal/service"
+	"project/internal/testutil"
+	"project/internal/utils/validator"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// userTestApp adalah route /api/users tanpa JWT dan rate limit di atas SQLite in-memory
+type userTestApp struct {
+	app     *fiber.App
+	service service.UserService
+	db      *gorm.DB
+}
+
+func newUserTestApp(t *testing.T, requireIfMatch bool) *userTestApp {
+	t.Helper()
+	db := testutil.NewDB(t)
+	testutil.CreateRoles(t, db, "admin", "viewer")
+
+	userRepository := repository.NewUserRepository(db)
+	validator.InitValidator()
+	validator.UseRepositories(userRepository, repository.NewRoleRepository(db))
+	t.Cleanup(func() { validator.UseRepositories(nil, nil) })
+
+	userService := service.NewUserService(userRepository, repository.NewTransactionManager(db))
+	userHandler := handler.NewUserHandler(userService, requireIfMatch)
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Use(middleware.Locale())
+	users := app.Group("/api/users")
+	users.Get("/:id", userHandler.GetUserByID)
+	users.Post("/", userHandler.CreateUser)
+	users.Put("/:id", userHandler.UpdateUser)
+	users.Patch("/:id", userHandler.PatchUser)
+	users.Delete("/:id", userHandler.DeleteUser)
+	return &userTestApp{app: app, service: userService, db: db}
+}
+
+// createUser menyimpan user viewer langsung lewat service
+func (a *userTestApp) createUser(t *testing.T, username string) models.User {
+	t.Helper()
+	user := models.User{Username: username, Password: "hash-" + username, Role: "viewer"}
+	if err := a.service.CreateUser(context.Background(), &user); err != nil {
+		t.Fatalf("CreateUser(%s): %v", username, err)
+	}
+	return user
+}
+
+// do mengirim request; headers berisi pasangan nama dan nilai
+func (a *userTestApp) do(t *testing.T, method, path, body string, headers ...string) (*http.Response, []byte) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	return resp, data
+}
+
+// problem mem-parsing respons application/problem+json
+func problem(t *testing.T, body []byte) handler.Problem {
+	t.Helper()
+	var p handler.Problem
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatalf("decode problem %s: %v", body, err)
+	}
+	return p
+}
+
+func TestUserRoutesRejectInvalidID(t *testing.T) {
+	a := newUserTestApp(t, false)
+
+	tests := []struct {
+		method string
+		body   string
+	}{
+		{fiber.MethodGet, ""},
+		{fiber.MethodPut, `{"username":"put@mail.com","role":"viewer"}`},
+		{fiber.MethodPatch, `{"role":"admin"}`},
+		{fiber.MethodDelete, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			resp, body := a.do(t, tt.method, "/api/users/not-a-uuid", tt.body)
+			if resp.StatusCode != fiber.StatusBadRequest {
+				t.Fatalf("status = %d %s, want 400", resp.StatusCode, body)
+			}
+			if p := problem(t, body); p.Code != "invalid_id" || p.Errors["id"] == "" {
+				t.Errorf("problem = %+v, want invalid_id with an id field error", p)
+			}
+		})
+	}
+}
+
+func TestUpdateUserValidatesUsername(t *testing.T) {
+	a := newUserTestApp(t, false)
+	user := a.createUser(t, "put@mail.com")
+
+	resp, body := a.do(t, fiber.MethodPut, "/api/users/"+user.ID.String(), `{"username":"not-an-email","role":"viewer"}`)
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("status = %d %s, want 400", resp.StatusCode, body)
+	}
+	if p := problem(t, body); p.Code != "validation_failed" || p.Errors["username"] == "" {
+		t.Errorf("problem = %+v, want validation_failed with a username error", p)
+	}
+}
@@ -0,0 +1,12 @@
+package handler
+
+import (
+	"project/internal/service"
+)
+
+var (
+	// errInvalidPayload - Body request bukan JSON yang valid
+	errInvalidPayload = service.Validation("invalid_payload", "Invalid request payload", nil)
+	// errInvalidID - Parameter :id bukan UUID
+	errInvalidID = service.Validation("invalid_id", "Invalid ID format", map[string]string{"id": "uuid"})
+)
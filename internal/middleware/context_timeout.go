@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContextTimeout memasang context dengan batas waktu pada setiap request.
+// Context ini (c.UserContext()) diteruskan handler ke service dan repository
+// sehingga query database dibatalkan ketika batas waktu terlewati.
+// Hanya batas waktu ini yang membatalkan query: context request Fiber (fasthttp)
+// tidak dibatalkan saat client memutus koneksi, jadi query tetap berjalan sampai
+// selesai atau sampai timeout. timeout <= 0 berarti tanpa batas waktu.
+func ContextTimeout(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
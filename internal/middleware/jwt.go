@@ -1,17 +1,40 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/service"
+	"project/pkg/i18n"
+	"project/pkg/metrics"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
 )
 
-// JWTProtected memvalidasi token JWT dan mengekstrak klaim user
-func JWTProtected(secret string) fiber.Handler {
+var (
+	errTokenMissing = service.Unauthorized("token_missing", "Missing or malformed JWT")
+	errTokenInvalid = service.Unauthorized("token_invalid", "Invalid or expired JWT")
+	errTokenClaims  = service.Unauthorized("token_claims_invalid", "Invalid token claims")
+)
+
+// UserLookup memuat user pemilik token beserta Roles (service.UserService memenuhinya)
+type UserLookup interface {
+	FindUserByID(ctx context.Context, id string) (*models.User, error)
+}
+
+// JWTProtected memvalidasi token JWT lalu memuat user pemiliknya dari database di
+// setiap request: token milik user yang sudah dihapus atau dinonaktifkan ditolak
+// meskipun belum kedaluwarsa, dan role untuk RequireRole diambil dari data terbaru
+// (users.role dan relasi Roles), bukan dari klaim token.
+// Token yang ditolak dihitung di metric auth_token_validation_failures_total per alasan.
+func JWTProtected(secret string, users UserLookup, m *metrics.Metrics) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Mendapatkan token dari header Authorization
 		authHeader := c.Get("Authorization")
 		if authHeader == "" || len(authHeader) < 7 || authHeader[:7] != "Bearer " {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "missing or malformed JWT"})
+			m.TokenValidationFailed("missing")
+			return errTokenMissing
 		}
 
 		tokenString := authHeader[7:] // Menghilangkan "Bearer " dari header
@@ -23,19 +46,63 @@ func JWTProtected(secret string) fiber.Handler {
 
 		// Jika ada error atau token tidak valid
 		if err != nil || !token.Valid {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid or expired JWT"})
+			m.TokenValidationFailed(tokenFailureReason(err))
+			return errTokenInvalid
 		}
 
 		// Ekstrak klaim jika token valid
 		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			// Simpan klaim yang dibutuhkan ke dalam context untuk diakses di handler berikutnya
-			c.Locals("userId", claims["userId"])
-			c.Locals("userRole", claims["role"]) // misalkan klaim "role" digunakan untuk peran user
+			// Simpan klaim yang dibutuhkan ke dalam context untuk diakses di handler berikutnya.
+			// ID user ada di klaim "sub" (lihat service.GenerateToken).
+			userID, _ := claims["sub"].(string)
+			user, err := users.FindUserByID(c.UserContext(), userID)
+			switch {
+			case errors.Is(err, service.ErrUserNotFound):
+				m.TokenValidationFailed("unknown_user")
+				return errTokenInvalid
+			case err != nil:
+				return err
+			case user.DisabledAt != nil:
+				m.TokenValidationFailed("user_disabled")
+				return service.ErrUserDisabled
+			}
+			c.Locals("userID", userID)
+			c.Locals("userRole", user.Role)
+			c.Locals("userRoles", user.RoleNames())
+
+			// Pelaku yang dicatat di audit log untuk perubahan selama request ini
+			c.SetUserContext(service.WithActor(c.UserContext(), service.Actor{
+				ID:       userID,
+				Username: user.Username,
+				IP:       c.IP(),
+			}))
+
+			// Bahasa pilihan user (users.locale) lebih diutamakan daripada Accept-Language
+			if locale, _ := claims["locale"].(string); i18n.Supported(locale) {
+				setLocale(c, locale)
+			}
 		} else {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid token claims"})
+			m.TokenValidationFailed("invalid_claims")
+			return errTokenClaims
 		}
 
 		// Jika valid, lanjutkan ke handler berikutnya
 		return c.Next()
 	}
 }
+
+// tokenFailureReason mengelompokkan error validasi JWT menjadi label metric
+func tokenFailureReason(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "expired"
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return "not_valid_yet"
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return "malformed"
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return "invalid_signature"
+	default:
+		return "invalid"
+	}
+}
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"project/internal/handler"
+	"project/internal/middleware"
+	"project/internal/models"
+	"project/internal/service"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// fakeUsers adalah middleware.UserLookup di memori, per ID user
+type fakeUsers map[string]*models.User
+
+func (f fakeUsers) FindUserByID(_ context.Context, id string) (*models.User, error) {
+	user, ok := f[id]
+	if !ok {
+		return nil, service.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func TestJWTProtectedChecksCurrentUser(t *testing.T) {
+	now := time.Now()
+	admin := models.User{ID: uuid.New(), Username: "admin@mail.com", Role: "viewer", Roles: []models.Role{{Name: "superadmin"}}}
+	viewer := models.User{ID: uuid.New(), Username: "viewer@mail.com", Role: "viewer"}
+	disabled := models.User{ID: uuid.New(), Username: "disabled@mail.com", Role: "superadmin", DisabledAt: &now}
+	deleted := models.User{ID: uuid.New(), Username: "deleted@mail.com", Role: "superadmin"}
+	users := fakeUsers{
+		admin.ID.String():    &admin,
+		viewer.ID.String():   &viewer,
+		disabled.ID.String(): &disabled,
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Get("/admin", middleware.JWTProtected(testSecret, users, nil), middleware.RequireRole("superadmin"), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	tests := []struct {
+		name string
+		// token dibuat dari data user saat login; role di token sengaja bisa berbeda
+		user models.User
+		want int
+	}{
+		{"role from association", admin, fiber.StatusOK},
+		{"claim role ignored", models.User{ID: viewer.ID, Username: viewer.Username, Role: "superadmin"}, fiber.StatusForbidden},
+		{"disabled after login", disabled, fiber.StatusForbidden},
+		{"deleted after login", deleted, fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := service.GenerateToken(testSecret, tt.user, time.Hour)
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+			req := httptest.NewRequest(fiber.MethodGet, "/admin", nil)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
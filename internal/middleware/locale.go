@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"project/pkg/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Locale memilih bahasa respons dari header Accept-Language (lihat i18n.Negotiate),
+// menyimpannya di c.UserContext() dan mengembalikannya di header Content-Language.
+// Bahasa pilihan user di token JWT menggantikannya (lihat JWTProtected).
+func Locale() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		setLocale(c, i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage)))
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+}
+
+func setLocale(c *fiber.Ctx, locale string) {
+	c.SetUserContext(i18n.WithLocale(c.UserContext(), locale))
+	c.Set(fiber.HeaderContentLanguage, locale)
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"project/pkg/metrics"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics mencatat jumlah dan latensi request per method, template route
+// (contoh /api/users/:id, bukan path aslinya) dan status code
+func Metrics(m *metrics.Metrics) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// Status dari error yang belum ditulis oleh error handler Fiber
+			status = fiber.StatusInternalServerError
+			if fiberErr, ok := err.(*fiber.Error); ok {
+				status = fiberErr.Code
+			}
+		}
+
+		// c.Method() memakai buffer yang dipakai ulang Fiber, jadi disalin karena
+		// label metric disimpan lebih lama dari request
+		m.ObserveHTTPRequest(strings.Clone(c.Method()), c.Route().Path, status, time.Since(start))
+		return err
+	}
+}
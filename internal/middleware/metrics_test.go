@@ -0,0 +1,101 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"project/internal/handler"
+	"project/internal/middleware"
+	"project/internal/models"
+	"project/internal/service"
+	"project/pkg/metrics"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const testSecret = "metrics-test-secret"
+
+// counterValue mengembalikan nilai counter name dengan label persis labels, atau 0
+func counterValue(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			if len(metric.GetLabel()) != len(labels) {
+				continue
+			}
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] != label.GetValue() {
+					continue metrics
+				}
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestMetricsAndTokenFailures(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := metrics.New(registry)
+
+	// Urutan sama dengan serve: Metrics, RequestLogger (menulis error), lalu route
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Use(middleware.Metrics(m))
+	app.Use(middleware.RequestLogger())
+	user := models.User{ID: uuid.New(), Username: "metrics@mail.com", Role: "superadmin"}
+	users := fakeUsers{user.ID.String(): &user}
+	app.Get("/api/users/:id", middleware.JWTProtected(testSecret, users, m), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	valid, err := service.GenerateToken(testSecret, user, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	expired, err := service.GenerateToken(testSecret, user, -time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	forged, err := service.GenerateToken("other-secret", user, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	for _, authorization := range []string{"", "Bearer " + expired, "Bearer " + forged, "Bearer not-a-jwt", "Bearer " + valid} {
+		req := httptest.NewRequest(fiber.MethodGet, "/api/users/"+uuid.NewString(), nil)
+		if authorization != "" {
+			req.Header.Set(fiber.HeaderAuthorization, authorization)
+		}
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("request: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   float64
+	}{
+		{"app_http_requests_total", map[string]string{"method": "GET", "route": "/api/users/:id", "status": "401"}, 4},
+		{"app_http_requests_total", map[string]string{"method": "GET", "route": "/api/users/:id", "status": "200"}, 1},
+		{"app_auth_token_validation_failures_total", map[string]string{"reason": "missing"}, 1},
+		{"app_auth_token_validation_failures_total", map[string]string{"reason": "expired"}, 1},
+		{"app_auth_token_validation_failures_total", map[string]string{"reason": "invalid_signature"}, 1},
+		{"app_auth_token_validation_failures_total", map[string]string{"reason": "malformed"}, 1},
+	}
+	for _, tt := range tests {
+		if got := counterValue(t, registry, tt.name, tt.labels); got != tt.want {
+			t.Errorf("%s%v = %v, want %v", tt.name, tt.labels, got, tt.want)
+		}
+	}
+}
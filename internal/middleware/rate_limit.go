@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"project/pkg/config"
+	"project/pkg/logger"
+	"project/pkg/metrics"
+	"project/pkg/ratelimit"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Header respons rate limit (draft IETF "RateLimit header fields for HTTP")
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// log adalah logger komponen "http"
+var log = logger.New("http")
+
+// RateLimiter membatasi request per group route dan per principal dengan token bucket.
+// Aturan bisa diganti saat berjalan lewat Update (hot reload config).
+type RateLimiter struct {
+	store   ratelimit.Store
+	rules   atomic.Pointer[rateLimitRules]
+	metrics *metrics.Metrics
+}
+
+// rateLimitRules - Aturan yang sedang berlaku, diganti utuh oleh Update
+type rateLimitRules struct {
+	enabled    bool
+	groups     map[string]ratelimit.Limit
+	principals map[string]ratelimit.Limit
+}
+
+// NewRateLimiter membuat RateLimiter dari cfg.RateLimit; m boleh nil
+func NewRateLimiter(store ratelimit.Store, cfg *config.Config, m *metrics.Metrics) (*RateLimiter, error) {
+	limiter := &RateLimiter{store: store, metrics: m}
+	if err := limiter.Update(cfg); err != nil {
+		return nil, err
+	}
+	return limiter, nil
+}
+
+// Update mengganti aturan dengan cfg.RateLimit. Jika cfg tidak valid aturan lama
+// tetap dipakai. Bucket yang sudah ada tetap menyimpan token yang tersisa,
+// dibatasi kapasitas (burst) yang baru.
+func (l *RateLimiter) Update(cfg *config.Config) error {
+	rules := &rateLimitRules{
+		enabled:    cfg.RateLimit.Enabled,
+		groups:     make(map[string]ratelimit.Limit, len(cfg.RateLimit.Groups)),
+		principals: make(map[string]ratelimit.Limit, len(cfg.RateLimit.Principals)),
+	}
+	for group, rule := range cfg.RateLimit.Groups {
+		limit := ratelimit.Limit(rule)
+		if !limit.Valid() {
+			return fmt.Errorf("rate_limit.groups.%s: requests and period must be positive", group)
+		}
+		rules.groups[group] = limit
+	}
+	for _, override := range cfg.RateLimit.Principals {
+		limit := ratelimit.Limit(override.RateLimitRule)
+		if override.Principal == "" || !limit.Valid() {
+			return fmt.Errorf("rate_limit.principals: %q needs a principal, and requests and period must be positive", override.Principal)
+		}
+		rules.principals[override.Principal] = limit
+	}
+	l.rules.Store(rules)
+	return nil
+}
+
+// Limit mengembalikan middleware untuk group route. Group yang tidak ada di config
+// (atau rate limit dinonaktifkan) tidak dibatasi; aturan dibaca setiap request
+// sehingga perubahan lewat Update langsung berlaku. Pasang setelah JWTProtected
+// agar request dibatasi per user, bukan per IP.
+func (l *RateLimiter) Limit(group string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rules := l.rules.Load()
+		limit, ok := rules.groups[group]
+		if !rules.enabled || !ok {
+			return c.Next()
+		}
+
+		kind, principal := requestPrincipal(c)
+		if override, ok := rules.principals[principal]; ok {
+			limit = override
+		}
+
+		result, err := l.store.Take(c.UserContext(), group+"|"+principal, limit)
+		if err != nil {
+			// Store bermasalah: request tetap dilayani daripada menolak semua client
+			log.WarnContext(c.UserContext(), "rate limit store failed, request allowed", "group", group, "error", err)
+			return c.Next()
+		}
+
+		c.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		c.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Set(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+
+		if !result.Allowed {
+			l.metrics.RateLimited(group, kind)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			// Detail dilokalkan oleh handler.ErrorHandler memakai header Retry-After
+			return fiber.NewError(fiber.StatusTooManyRequests, "Too many requests")
+		}
+		return c.Next()
+	}
+}
+
+// requestPrincipal menentukan siapa yang dibatasi: user dari JWT, lalu alamat IP
+func requestPrincipal(c *fiber.Ctx) (kind, principal string) {
+	if userID, ok := c.Locals("userID").(string); ok && userID != "" {
+		return "user", "user:" + userID
+	}
+	return "ip", "ip:" + c.IP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"project/pkg/database"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReadYourWrites mengatur routing read replica per request.
+// Request yang mengubah data (POST/PUT/PATCH/DELETE) membaca dari primary supaya
+// pengecekan versi dan respons tidak memakai data replica yang tertinggal.
+// Request lain membaca dari replica sampai ada query tulis, setelah itu ke primary.
+func ReadYourWrites() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			c.SetUserContext(database.WithReadYourWrites(c.UserContext()))
+		default:
+			c.SetUserContext(database.UsePrimary(c.UserContext()))
+		}
+		return c.Next()
+	}
+}
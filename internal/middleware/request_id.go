@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"project/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID adalah header untuk menerima dan mengembalikan request ID
+const HeaderRequestID = "X-Request-ID"
+
+// RequestID memakai X-Request-ID dari client (atau membuat UUID baru jika kosong
+// atau tidak valid), mengembalikannya di header respons, dan menyimpannya di
+// c.UserContext() sehingga setiap log dengan context tersebut mencantumkan request_id.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(HeaderRequestID, requestID)
+		c.Locals("requestID", requestID)
+		c.SetUserContext(logger.WithRequestID(c.UserContext(), requestID))
+		return c.Next()
+	}
+}
+
+// validRequestID membatasi request ID dari client agar aman ditulis ke log
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > 128 {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log/slog"
+	"project/pkg/logger"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestLogger mencatat setiap request (komponen "http") setelah selesai diproses.
+// Status 5xx dicatat sebagai error, 4xx sebagai warn, selainnya info.
+// Pasang setelah RequestID agar request_id ikut tercatat.
+func RequestLogger() fiber.Handler {
+	log := logger.New("http")
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		if err != nil {
+			// Biarkan error handler Fiber menulis respons agar status yang dicatat sesuai
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				c.Status(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("route", c.Route().Path),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("ip", c.IP()),
+			slog.Int("bytes", len(c.Response().Body())),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		log.Log(c.UserContext(), level, "request", attrs...)
+		return nil
+	}
+}
@@ -1,20 +1,27 @@
 package middleware
 
 import (
+	"errors"
 	"project/internal/service"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// RequireRole adalah middleware untuk memastikan user memiliki role tertentu
+// errAccessDenied - User tidak memiliki role atau permission yang dibutuhkan
+var errAccessDenied = service.Forbidden("access_denied", "Access denied")
+
+// RequireRole adalah middleware untuk memastikan user memiliki role tertentu, baik
+// sebagai role utama maupun lewat relasi Roles. Pasang setelah JWTProtected.
 func RequireRole(requiredRole string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Mengambil role user dari context
-		userRole := c.Locals("userRole")
-		if userRole != requiredRole {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+		// Mengambil role user dari context (diisi JWTProtected dari database)
+		userRoles, _ := c.Locals("userRoles").([]string)
+		for _, role := range userRoles {
+			if role == requiredRole {
+				return c.Next()
+			}
 		}
-		return c.Next()
+		return errAccessDenied
 	}
 }
 
@@ -22,12 +29,15 @@ func RequireRole(requiredRole string) fiber.Handler {
 func RequirePermission(requiredPermission string, userService service.UserService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Ambil user ID dari context (misalnya, setelah JWT divalidasi)
-		userID := c.Locals("userID").(string)
+		userID, _ := c.Locals("userID").(string)
 
-		// Ambil user dari database
-		user, err := userService.GetUserByID(userID)
+		// Ambil user dari database; user yang sudah dihapus tidak punya akses
+		user, err := userService.GetUserByID(c.UserContext(), userID)
+		if errors.Is(err, service.ErrUserNotFound) {
+			return errAccessDenied
+		}
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "User not found"})
+			return err
 		}
 
 		// Memeriksa apakah user memiliki permission yang diperlukan
@@ -40,7 +50,7 @@ func RequirePermission(requiredPermission string, userService service.UserServic
 		}
 
 		if !hasPermission {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+			return errAccessDenied
 		}
 
 		return c.Next()
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// AuditLog - Satu catatan perubahan administratif (append-only).
+// Hash dihitung dari isi baris dan PrevHash (hash baris sebelumnya), sehingga
+// baris yang diubah atau dihapus memutus rantai hash.
+type AuditLog struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ActorID       string    `gorm:"not null" json:"actor_id"`
+	ActorUsername string    `gorm:"not null" json:"actor_username"`
+	Action        string    `gorm:"not null" json:"action"`
+	TargetType    string    `gorm:"not null" json:"target_type"`
+	TargetID      string    `gorm:"not null" json:"target_id"`
+	Changes       JSONText  `gorm:"type:text;not null" json:"changes" swaggertype:"object"`
+	IP            string    `gorm:"not null" json:"ip"`
+	RequestID     string    `gorm:"not null" json:"request_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	PrevHash      string    `gorm:"not null;unique" json:"prev_hash"`
+	Hash          string    `gorm:"not null;unique" json:"hash"`
+}
+
+// JSONText - Dokumen JSON yang disimpan sebagai teks apa adanya dan ditulis
+// sebagai JSON (bukan string) di respons API
+type JSONText string
+
+// MarshalJSON menulis isi JSONText tanpa di-quote
+func (j JSONText) MarshalJSON() ([]byte, error) {
+	if j == "" {
+		return []byte("null"), nil
+	}
+	return []byte(j), nil
+}
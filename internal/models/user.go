@@ -12,14 +12,33 @@ type User struct {
 	Username    string       `gorm:"unique" json:"username"`
 	Password    string       `gorm:"unique;not null" json:"-"`
 	Role        string       `gorm:"not null" json:"role"`
+	Version     uint         `gorm:"not null;default:1" json:"version"`
 	Roles       []Role       `gorm:"many2many:user_roles;"`
 	Permissions []Permission `gorm:"many2many:user_permissions;"`
 	CreatedAt   time.Time    `json:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at"`
 	DeletedAt   *time.Time   `json:"deleted_at,omitempty"`
+	DisabledAt  *time.Time   `json:"disabled_at,omitempty"`
+	// Locale - Bahasa respons pilihan user (contoh "id"); kosong berarti mengikuti Accept-Language
+	Locale string `gorm:"size:10" json:"locale,omitempty"`
 }
 
 func (user *User) BeforeCreate(tx *gorm.DB) (err error) {
 	user.ID = uuid.New()
+	if user.Version == 0 {
+		user.Version = 1
+	}
 	return
 }
+
+// RoleNames mengembalikan role yang dimiliki user: Role (role utama) lalu nama
+// setiap role di relasi Roles, tanpa duplikat. Roles harus sudah di-preload.
+func (user User) RoleNames() []string {
+	names := []string{user.Role}
+	for _, role := range user.Roles {
+		if role.Name != user.Role {
+			names = append(names, role.Name)
+		}
+	}
+	return names
+}
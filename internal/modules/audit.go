@@ -0,0 +1,69 @@
+package modules
+
+import (
+	"project/internal/container"
+	"project/internal/handler"
+	"project/internal/middleware"
+	"project/internal/repository"
+	"project/internal/service"
+	"project/pkg/config"
+	"project/pkg/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// audit menyediakan audit log perubahan administratif
+type audit struct{}
+
+// Audit menyediakan service.AuditService dan route /audit (hanya superadmin)
+func Audit() container.Module { return audit{} }
+
+func (audit) Name() string { return "audit" }
+
+func (audit) Provide(c *container.Container) {
+	container.Provide(c, func(c *container.Container) (repository.AuditRepository, error) {
+		return repository.NewAuditRepository(container.MustResolve[*gorm.DB](c)), nil
+	})
+	container.Provide(c, func(c *container.Container) (service.AuditService, error) {
+		auditRepository, err := container.Resolve[repository.AuditRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		txManager, err := container.Resolve[repository.TransactionManager](c)
+		if err != nil {
+			return nil, err
+		}
+		return service.NewAuditService(auditRepository, txManager), nil
+	})
+	container.Provide(c, func(c *container.Container) (*handler.AuditHandler, error) {
+		auditService, err := container.Resolve[service.AuditService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handler.NewAuditHandler(auditService), nil
+	})
+}
+
+func (audit) Routes(router fiber.Router, c *container.Container) error {
+	auditHandler, err := container.Resolve[*handler.AuditHandler](c)
+	if err != nil {
+		return err
+	}
+	userService, err := container.Resolve[service.UserService](c)
+	if err != nil {
+		return err
+	}
+	limiter, err := container.Resolve[*middleware.RateLimiter](c)
+	if err != nil {
+		return err
+	}
+	cfg := container.MustResolve[*config.Config](c)
+	m := container.MustResolve[*metrics.Metrics](c)
+
+	// Audit log perubahan administratif, hanya untuk superadmin
+	auditRoutes := router.Group("/audit", middleware.JWTProtected(cfg.App.JWTSecret, userService, m), limiter.Limit("audit"), middleware.RequireRole("superadmin"))
+	auditRoutes.Get("/", auditHandler.GetAuditLogs)
+	auditRoutes.Get("/verify", auditHandler.VerifyAuditLogs)
+	return nil
+}
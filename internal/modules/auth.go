@@ -0,0 +1,40 @@
+package modules
+
+import (
+	"project/internal/container"
+	"project/internal/handler"
+	"project/internal/middleware"
+	"project/internal/service"
+	"project/pkg/config"
+	"project/pkg/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// auth menyediakan login dan profil; memakai service.UserService dari modul users
+type auth struct{}
+
+// Auth menyediakan route /login dan /profile
+func Auth() container.Module { return auth{} }
+
+func (auth) Name() string { return "auth" }
+
+func (auth) Provide(*container.Container) {}
+
+func (auth) Routes(router fiber.Router, c *container.Container) error {
+	userService, err := container.Resolve[service.UserService](c)
+	if err != nil {
+		return err
+	}
+	limiter, err := container.Resolve[*middleware.RateLimiter](c)
+	if err != nil {
+		return err
+	}
+	cfg := container.MustResolve[*config.Config](c)
+	m := container.MustResolve[*metrics.Metrics](c)
+
+	// Route untuk autentikasi dan profil, mengirimkan userService ke Login
+	router.Post("/login", limiter.Limit("login"), handler.Login(cfg.App.JWTSecret, userService, m))
+	router.Get("/profile", middleware.JWTProtected(cfg.App.JWTSecret, userService, m), handler.Profile)
+	return nil
+}
@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"context"
+	"project/internal/container"
+	"project/internal/middleware"
+	"project/internal/repository"
+	"project/pkg/config"
+	"project/pkg/metrics"
+	"project/pkg/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Default mengembalikan modul aplikasi: Core lalu semua modul fitur. Route
+// didaftarkan sesuai urutan ini. m boleh nil jika metrics tidak dipakai (CLI, test).
+func Default(cfg *config.Config, db *gorm.DB, m *metrics.Metrics) []container.Module {
+	return []container.Module{
+		Core(cfg, db, m),
+		Roles(),
+		Audit(),
+		Users(),
+		Auth(),
+		// {generate} Modul dari `go run ./cmd generate module` ditambahkan di atas baris ini
+	}
+}
+
+// core menyediakan dependency bersama untuk semua modul
+type core struct {
+	cfg *config.Config
+	db  *gorm.DB
+	m   *metrics.Metrics
+}
+
+// Core menyediakan *config.Config, *gorm.DB, *metrics.Metrics (boleh nil),
+// repository.TransactionManager dan *middleware.RateLimiter
+func Core(cfg *config.Config, db *gorm.DB, m *metrics.Metrics) container.Module {
+	return core{cfg: cfg, db: db, m: m}
+}
+
+func (core) Name() string { return "core" }
+
+func (m core) Provide(c *container.Container) {
+	container.Supply(c, m.cfg)
+	container.Supply(c, m.db)
+	container.Supply(c, m.m)
+	container.Provide(c, func(c *container.Container) (repository.TransactionManager, error) {
+		return repository.NewTransactionManager(container.MustResolve[*gorm.DB](c)), nil
+	})
+
+	// Rate limit per group route dan principal; state bucket disimpan di memori
+	// instance ini dan dibersihkan saat container berhenti
+	container.Provide(c, func(c *container.Container) (*middleware.RateLimiter, error) {
+		cfg := container.MustResolve[*config.Config](c)
+		store := ratelimit.NewMemoryStore(cfg.RateLimit.CleanupInterval)
+		limiter, err := middleware.NewRateLimiter(store, cfg, container.MustResolve[*metrics.Metrics](c))
+		if err != nil {
+			store.Close()
+			return nil, err
+		}
+		c.Lifecycle().Append(container.Hook{Name: "rate limit store", OnStop: closer(store.Close)})
+		return limiter, nil
+	})
+}
+
+func (core) Routes(fiber.Router, *container.Container) error { return nil }
+
+// closer mengubah fungsi Close tanpa error menjadi Hook.OnStop
+func closer(close func()) func(context.Context) error {
+	return func(context.Context) error {
+		close()
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package modules
+
+import (
+	"project/internal/container"
+	"project/internal/repository"
+	"project/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// roles menyediakan repository dan service role; belum ada route HTTP untuk role
+type roles struct{}
+
+// Roles menyediakan repository.RoleRepository dan service.RoleService (dicatat di audit log)
+func Roles() container.Module { return roles{} }
+
+func (roles) Name() string { return "roles" }
+
+func (roles) Provide(c *container.Container) {
+	container.Provide(c, func(c *container.Container) (repository.RoleRepository, error) {
+		return repository.NewRoleRepository(container.MustResolve[*gorm.DB](c)), nil
+	})
+	container.Provide(c, func(c *container.Container) (service.RoleService, error) {
+		roleRepository, err := container.Resolve[repository.RoleRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		userRepository, err := container.Resolve[repository.UserRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		txManager, err := container.Resolve[repository.TransactionManager](c)
+		if err != nil {
+			return nil, err
+		}
+		auditService, err := container.Resolve[service.AuditService](c)
+		if err != nil {
+			return nil, err
+		}
+		return service.NewAuditedRoleService(service.NewRoleService(roleRepository, userRepository, txManager), auditService), nil
+	})
+}
+
+func (roles) Routes(fiber.Router, *container.Container) error { return nil }
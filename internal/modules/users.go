@@ -0,0 +1,115 @@
+package modules
+
+import (
+	"context"
+	"project/internal/container"
+	"project/internal/handler"
+	"project/internal/middleware"
+	"project/internal/repository"
+	"project/internal/service"
+	"project/internal/utils/validator"
+	"project/pkg/config"
+	"project/pkg/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// users menyediakan manajemen user
+type users struct{}
+
+// Users menyediakan repository.UserRepository, service.UserService (dicatat di
+// audit log dan sebagai span tracing), *handler.UserHandler dan route /users
+func Users() container.Module { return users{} }
+
+func (users) Name() string { return "users" }
+
+func (users) Provide(c *container.Container) {
+	container.Provide(c, func(c *container.Container) (repository.UserRepository, error) {
+		return repository.NewUserRepository(container.MustResolve[*gorm.DB](c)), nil
+	})
+	container.Provide(c, func(c *container.Container) (service.UserService, error) {
+		userRepository, err := container.Resolve[repository.UserRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		txManager, err := container.Resolve[repository.TransactionManager](c)
+		if err != nil {
+			return nil, err
+		}
+		auditService, err := container.Resolve[service.AuditService](c)
+		if err != nil {
+			return nil, err
+		}
+		return service.NewTracedUserService(service.NewAuditedUserService(service.NewUserService(userRepository, txManager), auditService)), nil
+	})
+	container.Provide(c, func(c *container.Container) (*handler.UserHandler, error) {
+		userService, err := container.Resolve[service.UserService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handler.NewUserHandler(userService, container.MustResolve[*config.Config](c).App.RequireIfMatch), nil
+	})
+
+	// Aturan validasi role_exists dan unique_username memeriksa database lewat repository
+	c.Lifecycle().Append(container.Hook{Name: "validator repositories", OnStart: func(context.Context) error {
+		userRepository, err := container.Resolve[repository.UserRepository](c)
+		if err != nil {
+			return err
+		}
+		roleRepository, err := container.Resolve[repository.RoleRepository](c)
+		if err != nil {
+			return err
+		}
+		validator.UseRepositories(userRepository, roleRepository)
+		return nil
+	}})
+}
+
+func (users) Routes(router fiber.Router, c *container.Container) error {
+	userHandler, err := container.Resolve[*handler.UserHandler](c)
+	if err != nil {
+		return err
+	}
+	userService, err := container.Resolve[service.UserService](c)
+	if err != nil {
+		return err
+	}
+	limiter, err := container.Resolve[*middleware.RateLimiter](c)
+	if err != nil {
+		return err
+	}
+	cfg := container.MustResolve[*config.Config](c)
+	m := container.MustResolve[*metrics.Metrics](c)
+
+	// Group untuk route user yang membutuhkan autentikasi dan otorisasi admin;
+	// rate limit dipasang setelah JWT agar dihitung per user
+	userRoutes := router.Group("/users", middleware.JWTProtected(cfg.App.JWTSecret, userService, m), limiter.Limit("users"))
+
+	//routes untuk testing tanpa middleware
+	// userRoutes := router.Group("/users")
+
+	// {Jangan Dihapus} Routes untuk User dengan akses role admin
+	userRoutes.Get("/", middleware.RequireRole("superadmin"), userHandler.GetAllUsers)
+	userRoutes.Get("/:id", middleware.RequireRole("superadmin"), userHandler.GetUserByID)
+	userRoutes.Post("/", middleware.RequireRole("superadmin"), userHandler.CreateUser)
+	userRoutes.Put("/:id", middleware.RequireRole("superadmin"), userHandler.UpdateUser)
+	userRoutes.Patch("/:id", middleware.RequireRole("superadmin"), userHandler.PatchUser)
+	userRoutes.Delete("/:id", middleware.RequireRole("superadmin"), userHandler.DeleteUser)
+
+	// {Testing} routes tanpa middleware
+	userRoutes.Get("/", userHandler.GetAllUsers)
+	userRoutes.Get("/:id", userHandler.GetUserByID)
+	userRoutes.Post("/", userHandler.CreateUser)
+	userRoutes.Put("/:id", userHandler.UpdateUser)
+	userRoutes.Patch("/:id", userHandler.PatchUser)
+	userRoutes.Delete("/:id", userHandler.DeleteUser)
+
+	// Jika Anda ingin menggunakan permission-based access di masa depan:
+	// userService := container.MustResolve[service.UserService](c)
+	// userRoutes.Get("/:id", middleware.RequirePermission("view_user", userService), userHandler.GetUserByID)
+	// userRoutes.Post("/", middleware.RequirePermission("create_user", userService), userHandler.CreateUser)
+	// userRoutes.Put("/:id", middleware.RequirePermission("edit_user", userService), userHandler.UpdateUser)
+	// userRoutes.Delete("/:id", middleware.RequirePermission("delete_user", userService), userHandler.DeleteUser)
+	return nil
+}
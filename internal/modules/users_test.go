@@ -0,0 +1,190 @@
+package modules_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"project/internal/container"
+	"project/internal/handler"
+	"project/internal/models"
+	"project/internal/modules"
+	"project/internal/routes"
+	"project/internal/service"
+	"project/internal/testutil"
+	"project/pkg/config"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const testSecret = "modules-test-secret"
+
+// fakeUserService menggantikan service.UserService; method yang tidak di-override
+// panic karena interface yang di-embed nil
+type fakeUserService struct {
+	service.UserService
+	user  models.User
+	calls int
+}
+
+func (f *fakeUserService) GetUserByID(_ context.Context, id string) (models.User, error) {
+	f.calls++
+	if id != f.user.ID.String() {
+		return models.User{}, service.ErrUserNotFound
+	}
+	return f.user, nil
+}
+
+// FindUserByID dipakai JWTProtected untuk memuat pemilik token
+func (f *fakeUserService) FindUserByID(_ context.Context, id string) (*models.User, error) {
+	if id != f.user.ID.String() {
+		return nil, service.ErrUserNotFound
+	}
+	user := f.user
+	return &user, nil
+}
+
+// hookRecorder mencatat urutan OnStart/OnStop hook
+type hookRecorder struct {
+	events []string
+}
+
+func (r *hookRecorder) hook(name string, startErr error) container.Hook {
+	return container.Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			r.events = append(r.events, "start "+name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			r.events = append(r.events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func testConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.App.JWTSecret = testSecret
+	cfg.Database.QueryTimeout = 5 * time.Second
+	return cfg
+}
+
+// newTestContainer menyusun modul default di atas SQLite in-memory lalu mengganti
+// service.UserService dengan fake. Constructor fake menambahkan hook "user service",
+// seperti provider yang membuka resource saat pertama kali di-Resolve.
+func newTestContainer(t *testing.T, fake *fakeUserService, recorder *hookRecorder) *container.Container {
+	t.Helper()
+	c := container.New(modules.Default(testConfig(), testutil.NewDB(t), nil)...)
+	container.Provide(c, func(c *container.Container) (service.UserService, error) {
+		c.Lifecycle().Append(recorder.hook("user service", nil))
+		return fake, nil
+	})
+	return c
+}
+
+func TestUsersModuleUsesProvidedUserService(t *testing.T) {
+	fake := &fakeUserService{user: models.User{ID: uuid.New(), Username: "fake@mail.com", Role: "superadmin"}}
+	c := newTestContainer(t, fake, &hookRecorder{})
+
+	resolved, err := container.Resolve[service.UserService](c)
+	if err != nil {
+		t.Fatalf("Resolve UserService: %v", err)
+	}
+	if resolved != service.UserService(fake) {
+		t.Fatalf("Resolve UserService = %T, want the fake", resolved)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	if err := routes.InitializeRoutes(app, c); err != nil {
+		t.Fatalf("InitializeRoutes: %v", err)
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { c.Stop(context.Background()) })
+
+	token, err := service.GenerateToken(testSecret, fake.user, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	req := httptest.NewRequest(fiber.MethodGet, "/api/users/"+fake.user.ID.String(), nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("GET /api/users/:id: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || !strings.Contains(string(body), fake.user.Username) {
+		t.Errorf("GET /api/users/:id = %d %s, want 200 with the fake user", resp.StatusCode, body)
+	}
+	if fake.calls != 1 {
+		t.Errorf("fake GetUserByID calls = %d, want 1", fake.calls)
+	}
+}
+
+func TestContainerHookOrder(t *testing.T) {
+	recorder := &hookRecorder{}
+	c := newTestContainer(t, &fakeUserService{}, recorder)
+
+	c.Lifecycle().Append(recorder.hook("first", nil))
+	if _, err := container.Resolve[*handler.UserHandler](c); err != nil {
+		t.Fatalf("Resolve UserHandler: %v", err)
+	}
+	c.Lifecycle().Append(recorder.hook("last", nil))
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	want := []string{
+		"start first", "start user service", "start last",
+		"stop last", "stop user service", "stop first",
+	}
+	if !reflect.DeepEqual(recorder.events, want) {
+		t.Errorf("hook events = %v, want %v", recorder.events, want)
+	}
+}
+
+func TestContainerStartRollsBackOnFailure(t *testing.T) {
+	recorder := &hookRecorder{}
+	c := newTestContainer(t, &fakeUserService{}, recorder)
+
+	errBoom := errors.New("boom")
+	c.Lifecycle().Append(recorder.hook("first", nil))
+	if _, err := container.Resolve[*handler.UserHandler](c); err != nil {
+		t.Fatalf("Resolve UserHandler: %v", err)
+	}
+	c.Lifecycle().Append(recorder.hook("failing", errBoom))
+	c.Lifecycle().Append(recorder.hook("never", nil))
+
+	err := c.Start(context.Background())
+	if !errors.Is(err, errBoom) || !strings.Contains(err.Error(), "start failing") {
+		t.Fatalf("Start error = %v, want start failing: boom", err)
+	}
+
+	// Hook yang gagal dan hook setelahnya tidak dihentikan karena belum berjalan
+	want := []string{
+		"start first", "start user service", "start failing",
+		"stop user service", "stop first",
+	}
+	if !reflect.DeepEqual(recorder.events, want) {
+		t.Errorf("hook events = %v, want %v", recorder.events, want)
+	}
+
+	// Stop setelah rollback tidak menghentikan hook dua kali
+	if err := c.Stop(context.Background()); err != nil {
+		t.Errorf("Stop after rollback: %v", err)
+	}
+	if len(recorder.events) != len(want) {
+		t.Errorf("hook events after Stop = %v, want no new events", recorder.events)
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditFilter - Filter daftar audit log; field kosong tidak dipakai
+type AuditFilter struct {
+	ActorID  string
+	TargetID string
+	Action   string
+	From     *time.Time
+	To       *time.Time
+}
+
+// AuditRepository hanya bisa menambah dan membaca audit log (append-only)
+type AuditRepository interface {
+	// Last mengembalikan baris terakhir rantai; ok false jika tabel masih kosong
+	Last(ctx context.Context) (entry models.AuditLog, ok bool, err error)
+	Create(ctx context.Context, entry *models.AuditLog) error
+	List(ctx context.Context, filter AuditFilter, page, limit int) ([]models.AuditLog, int64, error)
+	// Walk membaca semua baris berurutan dari id terkecil per batch
+	Walk(ctx context.Context, batchSize int, fn func(entries []models.AuditLog) error) error
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db}
+}
+
+// conn memakai transaksi dari ctx (lihat TransactionManager) jika ada
+func (r *auditRepository) conn(ctx context.Context) *gorm.DB {
+	return DB(ctx, r.db)
+}
+
+func (r *auditRepository) Last(ctx context.Context) (models.AuditLog, bool, error) {
+	var entry models.AuditLog
+	err := r.conn(ctx).Order("id DESC").Take(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.AuditLog{}, false, nil
+	}
+	if err != nil {
+		return models.AuditLog{}, false, translateError(err)
+	}
+	return entry, true, nil
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	return translateError(r.conn(ctx).Create(entry).Error)
+}
+
+// List mengembalikan audit log terbaru lebih dulu
+func (r *auditRepository) List(ctx context.Context, filter AuditFilter, page, limit int) ([]models.AuditLog, int64, error) {
+	query := r.conn(ctx).Model(&models.AuditLog{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, translateError(err)
+	}
+
+	var entries []models.AuditLog
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&entries).Error
+	if err != nil {
+		return nil, 0, translateError(err)
+	}
+	return entries, total, nil
+}
+
+func (r *auditRepository) Walk(ctx context.Context, batchSize int, fn func(entries []models.AuditLog) error) error {
+	var entries []models.AuditLog
+	err := r.conn(ctx).Order("id ASC").FindInBatches(&entries, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(entries)
+	}).Error
+	return translateError(err)
+}
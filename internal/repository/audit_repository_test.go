@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+	"project/internal/testutil"
+	"testing"
+)
+
+func TestAuditRepositoryAppendOnly(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewAuditRepository(db)
+	ctx := context.Background()
+
+	if _, ok, err := repo.Last(ctx); err != nil || ok {
+		t.Fatalf("Last on empty table = ok %v, err %v; want false, nil", ok, err)
+	}
+
+	entry := models.AuditLog{ActorID: "cli", ActorUsername: "root", Action: "user.create", TargetType: "user",
+		TargetID: "1", Changes: "{}", PrevHash: "genesis", Hash: "h1"}
+	if err := repo.Create(ctx, &entry); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	last, ok, err := repo.Last(ctx)
+	if err != nil || !ok || last.Hash != "h1" {
+		t.Fatalf("Last = %q ok %v err %v, want h1", last.Hash, ok, err)
+	}
+
+	// Trigger database menolak UPDATE dan DELETE
+	if err := db.Model(&models.AuditLog{}).Where("id = ?", entry.ID).Update("action", "user.delete").Error; err == nil {
+		t.Error("UPDATE audit_logs succeeded, want error")
+	}
+	if err := db.Delete(&models.AuditLog{}, entry.ID).Error; err == nil {
+		t.Error("DELETE audit_logs succeeded, want error")
+	}
+
+	entries, total, err := repo.List(ctx, AuditFilter{Action: "user.create"}, 1, 10)
+	if err != nil || total != 1 || len(entries) != 1 {
+		t.Errorf("List = %d entries (total %d, err %v), want 1", len(entries), total, err)
+	}
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Jenis error database yang dikembalikan repository; bandingkan dengan errors.Is.
+// Error aslinya tetap bisa diperiksa (contoh errors.Is(err, gorm.ErrRecordNotFound)).
+var (
+	// ErrNotFound - Data yang dicari tidak ada
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate - Melanggar unique constraint
+	ErrDuplicate = errors.New("duplicate key")
+	// ErrForeignKey - Data yang dirujuk tidak ada atau masih dirujuk data lain
+	ErrForeignKey = errors.New("foreign key violation")
+	// ErrNotNull - Kolom wajib diisi bernilai NULL
+	ErrNotNull = errors.New("not null violation")
+	// ErrUnavailable - Database tidak bisa dihubungi atau sedang menolak koneksi
+	ErrUnavailable = errors.New("database unavailable")
+)
+
+// DBError adalah error database yang sudah dikenali jenisnya
+type DBError struct {
+	// Kind adalah salah satu ErrNotFound, ErrDuplicate, ErrForeignKey, ErrNotNull atau ErrUnavailable
+	Kind error
+	// Column adalah kolom yang melanggar constraint, jika driver menyebutkannya
+	Column string
+	// Constraint adalah nama constraint yang dilanggar (hanya Postgres)
+	Constraint string
+	// Err adalah error asli dari gorm atau driver
+	Err error
+}
+
+func (e *DBError) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *DBError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Kode error Postgres (SQLSTATE)
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgTooManyConnections  = "53300"
+	pgAdminShutdown       = "57P01"
+	pgCrashShutdown       = "57P02"
+	pgCannotConnectNow    = "57P03"
+)
+
+// Kode error MySQL
+const (
+	mysqlDuplicateEntry   = 1062
+	mysqlRowIsReferenced  = 1451
+	mysqlNoReferencedRow  = 1452
+	mysqlBadNull          = 1048
+	mysqlNoDefault        = 1364
+	mysqlTooManyConns     = 1040
+	mysqlServerShutdown   = 1053
+	mysqlReadOnlyInstance = 1290
+)
+
+// Extended result code SQLite
+const (
+	sqliteBusy                 = 5
+	sqliteLocked               = 6
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintNotNull    = 1299
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// translateError mengubah error gorm/driver menjadi *DBError.
+// Error yang tidak dikenali dikembalikan apa adanya.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var dbErr *DBError
+	if errors.As(err, &dbErr) {
+		return err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &DBError{Kind: ErrNotFound, Err: err}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return translatePostgres(pgErr, err)
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return translateMySQL(mysqlErr, err)
+	}
+	// modernc.org/sqlite (dipakai glebarez/sqlite) mengembalikan extended result code
+	var sqliteErr interface{ Code() int }
+	if errors.As(err, &sqliteErr) {
+		return translateSQLite(sqliteErr.Code(), err)
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &connectErr),
+		errors.As(err, &netErr),
+		errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, sql.ErrConnDone),
+		errors.Is(err, mysql.ErrInvalidConn):
+		return &DBError{Kind: ErrUnavailable, Err: err}
+	}
+	return err
+}
+
+func translatePostgres(pgErr *pgconn.PgError, err error) error {
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		// Detail: Key (username)=(alice) already exists.
+		return &DBError{Kind: ErrDuplicate, Column: between(pgErr.Detail, "Key (", ")="), Constraint: pgErr.ConstraintName, Err: err}
+	case pgForeignKeyViolation:
+		return &DBError{Kind: ErrForeignKey, Column: between(pgErr.Detail, "Key (", ")="), Constraint: pgErr.ConstraintName, Err: err}
+	case pgNotNullViolation:
+		return &DBError{Kind: ErrNotNull, Column: pgErr.ColumnName, Err: err}
+	case pgTooManyConnections, pgAdminShutdown, pgCrashShutdown, pgCannotConnectNow:
+		return &DBError{Kind: ErrUnavailable, Err: err}
+	}
+	// Class 08 - Connection Exception
+	if strings.HasPrefix(pgErr.Code, "08") {
+		return &DBError{Kind: ErrUnavailable, Err: err}
+	}
+	return err
+}
+
+func translateMySQL(mysqlErr *mysql.MySQLError, err error) error {
+	switch mysqlErr.Number {
+	case mysqlDuplicateEntry:
+		// Duplicate entry 'alice' for key 'users.username'
+		return &DBError{Kind: ErrDuplicate, Column: unqualified(between(mysqlErr.Message, "for key '", "'")), Err: err}
+	case mysqlRowIsReferenced, mysqlNoReferencedRow:
+		return &DBError{Kind: ErrForeignKey, Err: err}
+	case mysqlBadNull, mysqlNoDefault:
+		// Column 'username' cannot be null / Field 'username' doesn't have a default value
+		return &DBError{Kind: ErrNotNull, Column: between(mysqlErr.Message, "'", "'"), Err: err}
+	case mysqlTooManyConns, mysqlServerShutdown, mysqlReadOnlyInstance:
+		return &DBError{Kind: ErrUnavailable, Err: err}
+	}
+	return err
+}
+
+func translateSQLite(code int, err error) error {
+	// constraint failed: UNIQUE constraint failed: users.username (2067)
+	message := err.Error()
+	if i := strings.LastIndex(message, "failed: "); i >= 0 {
+		message = message[i+len("failed: "):]
+	}
+	detail, _, _ := strings.Cut(message, " (")
+	column := unqualified(strings.TrimSpace(detail))
+	switch code {
+	case sqliteConstraintUnique, sqliteConstraintPrimaryKey:
+		return &DBError{Kind: ErrDuplicate, Column: column, Err: err}
+	case sqliteConstraintForeignKey:
+		return &DBError{Kind: ErrForeignKey, Err: err}
+	case sqliteConstraintNotNull:
+		return &DBError{Kind: ErrNotNull, Column: column, Err: err}
+	}
+	switch code & 0xff {
+	case sqliteBusy, sqliteLocked:
+		return &DBError{Kind: ErrUnavailable, Err: err}
+	}
+	return err
+}
+
+// between mengembalikan teks di antara start dan end pertama, atau "" jika tidak ada
+func between(s, start, end string) string {
+	rest := after(s, start)
+	if i := strings.Index(rest, end); i >= 0 {
+		return rest[:i]
+	}
+	return ""
+}
+
+// after mengembalikan teks setelah sep pertama, atau "" jika tidak ada
+func after(s, sep string) string {
+	if _, rest, ok := strings.Cut(s, sep); ok {
+		return rest
+	}
+	return ""
+}
+
+// unqualified membuang prefix nama tabel dan hanya menyimpan kolom pertama,
+// contoh "users.username, users.email" -> "username"
+func unqualified(column string) string {
+	column, _, _ = strings.Cut(column, ",")
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		column = column[i+1:]
+	}
+	return column
+}
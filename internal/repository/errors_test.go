@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"project/internal/testutil"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// sqliteCodeError meniru error modernc.org/sqlite yang punya method Code
+type sqliteCodeError struct{ code int }
+
+func (e sqliteCodeError) Error() string { return fmt.Sprintf("sqlite error (%d)", e.code) }
+func (e sqliteCodeError) Code() int     { return e.code }
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		kind   error
+		column string
+	}{
+		{"not found", gorm.ErrRecordNotFound, ErrNotFound, ""},
+		{"postgres unique", &pgconn.PgError{Code: "23505", Detail: "Key (username)=(alice) already exists.", ConstraintName: "users_username_key"}, ErrDuplicate, "username"},
+		{"postgres foreign key", &pgconn.PgError{Code: "23503", Detail: `Key (role_id)=(9) is not present in table "roles".`}, ErrForeignKey, "role_id"},
+		{"postgres not null", &pgconn.PgError{Code: "23502", ColumnName: "role"}, ErrNotNull, "role"},
+		{"postgres too many connections", &pgconn.PgError{Code: "53300"}, ErrUnavailable, ""},
+		{"postgres connection exception", &pgconn.PgError{Code: "08006"}, ErrUnavailable, ""},
+		{"mysql duplicate", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'alice' for key 'users.username'"}, ErrDuplicate, "username"},
+		{"mysql foreign key", &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, ErrForeignKey, ""},
+		{"mysql row referenced", &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"}, ErrForeignKey, ""},
+		{"mysql bad null", &mysql.MySQLError{Number: 1048, Message: "Column 'role' cannot be null"}, ErrNotNull, "role"},
+		{"mysql no default", &mysql.MySQLError{Number: 1364, Message: "Field 'role' doesn't have a default value"}, ErrNotNull, "role"},
+		{"mysql too many connections", &mysql.MySQLError{Number: 1040, Message: "Too many connections"}, ErrUnavailable, ""},
+		{"sqlite busy", sqliteCodeError{5}, ErrUnavailable, ""},
+		{"sqlite busy snapshot", sqliteCodeError{517}, ErrUnavailable, ""},
+		{"bad connection", driver.ErrBadConn, ErrUnavailable, ""},
+		{"connection done", sql.ErrConnDone, ErrUnavailable, ""},
+		{"mysql invalid connection", mysql.ErrInvalidConn, ErrUnavailable, ""},
+		{"network", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, ErrUnavailable, ""},
+		{"wrapped", fmt.Errorf("create user: %w", &pgconn.PgError{Code: "23505", Detail: "Key (password)=(x) already exists."}), ErrDuplicate, "password"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err)
+			var dbErr *DBError
+			if !errors.As(err, &dbErr) {
+				t.Fatalf("translateError(%v) = %v, want *DBError", tt.err, err)
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("Kind = %v, want %v", dbErr.Kind, tt.kind)
+			}
+			if dbErr.Column != tt.column {
+				t.Errorf("Column = %q, want %q", dbErr.Column, tt.column)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("translated error does not wrap the original %v", tt.err)
+			}
+		})
+	}
+}
+
+func TestTranslateErrorPassThrough(t *testing.T) {
+	if translateError(nil) != nil {
+		t.Error("translateError(nil) != nil")
+	}
+
+	unknown := []error{
+		errors.New("something else"),
+		&pgconn.PgError{Code: "42601", Message: "syntax error"},
+		&mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"},
+		sqliteCodeError{1},
+	}
+	for _, err := range unknown {
+		if got := translateError(err); got != err {
+			t.Errorf("translateError(%v) = %v, want the same error", err, got)
+		}
+	}
+
+	translated := translateError(gorm.ErrRecordNotFound)
+	if got := translateError(translated); got != translated {
+		t.Errorf("translateError(*DBError) = %v, want the same error", got)
+	}
+}
+
+// TestTranslateErrorSQLite memakai error constraint asli dari SQLite
+func TestTranslateErrorSQLite(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+	exec := func(query string, args ...interface{}) error {
+		return translateError(db.WithContext(ctx).Exec(query, args...).Error)
+	}
+
+	if err := exec("INSERT INTO users (id, username, password, role) VALUES ('u1', 'alice', 'p1', 'viewer')"); err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		kind   error
+		column string
+	}{
+		{"unique", "INSERT INTO users (id, username, password, role) VALUES ('u2', 'alice', 'p2', 'viewer')", ErrDuplicate, "username"},
+		{"primary key", "INSERT INTO users (id, username, password, role) VALUES ('u1', 'bob', 'p3', 'viewer')", ErrDuplicate, "id"},
+		{"foreign key", "INSERT INTO user_roles (user_id, role_id) VALUES ('u1', 999)", ErrForeignKey, ""},
+		{"not null", "INSERT INTO users (id, username, password) VALUES ('u3', 'carol', 'p4')", ErrNotNull, "role"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := exec(tt.query)
+			var dbErr *DBError
+			if !errors.As(err, &dbErr) {
+				t.Fatalf("error = %v, want *DBError", err)
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("Kind = %v, want %v", dbErr.Kind, tt.kind)
+			}
+			if dbErr.Column != tt.column {
+				t.Errorf("Column = %q, want %q", dbErr.Column, tt.column)
+			}
+		})
+	}
+
+	// Error lain dari SQLite (contoh syntax error) tidak diubah
+	err := exec("INSERT INTO missing_table VALUES (1)")
+	var dbErr *DBError
+	if err == nil || errors.As(err, &dbErr) {
+		t.Errorf("error for unknown table = %v, want untranslated error", err)
+	}
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	GetRoleByName(ctx context.Context, name string) (models.Role, error)
+	AddUserRole(ctx context.Context, userID uuid.UUID, roleID uint) error
+	RemoveUserRole(ctx context.Context, userID uuid.UUID, roleID uint) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db}
+}
+
+// conn memakai transaksi dari ctx (lihat TransactionManager) jika ada
+func (r *roleRepository) conn(ctx context.Context) *gorm.DB {
+	return DB(ctx, r.db)
+}
+
+// GetRoleByName mencari role berdasarkan nama
+func (r *roleRepository) GetRoleByName(ctx context.Context, name string) (models.Role, error) {
+	var role models.Role
+	err := r.conn(ctx).Where("name = ?", name).First(&role).Error
+	return role, translateError(err)
+}
+
+// AddUserRole mengaitkan role ke user; tidak melakukan apa-apa jika sudah terkait
+func (r *roleRepository) AddUserRole(ctx context.Context, userID uuid.UUID, roleID uint) error {
+	userRole := models.UserRole{UserID: userID, RoleID: roleID}
+	return translateError(r.conn(ctx).Where("user_id = ? AND role_id = ?", userID, roleID).FirstOrCreate(&userRole).Error)
+}
+
+// RemoveUserRole melepas role dari user
+func (r *roleRepository) RemoveUserRole(ctx context.Context, userID uuid.UUID, roleID uint) error {
+	return translateError(r.conn(ctx).Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&models.UserRole{}).Error)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey - Key context untuk menyimpan transaksi gorm yang sedang aktif
+type txKey struct{}
+
+// TransactionManager menjalankan beberapa pemanggilan repository secara atomik.
+// Repository yang dipanggil dengan ctx dari fn otomatis memakai transaksi yang sama.
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactionManager struct {
+	db *gorm.DB
+}
+
+func NewTransactionManager(db *gorm.DB) TransactionManager {
+	return &transactionManager{db}
+}
+
+// WithinTransaction membuka transaksi, menjalankan fn, lalu commit jika fn tidak
+// mengembalikan error (atau panic) dan rollback jika sebaliknya.
+// Jika ctx sudah membawa transaksi, fn ikut berjalan di transaksi tersebut.
+func (m *transactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	// Error dari fn biasanya sudah diterjemahkan repository; sisanya error begin/commit
+	return translateError(err)
+}
+
+// DB mengembalikan transaksi aktif di ctx, atau db jika tidak ada transaksi
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
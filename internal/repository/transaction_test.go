@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/testutil"
+	"testing"
+)
+
+func TestWithinTransactionRollsBack(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db)
+	txManager := NewTransactionManager(db)
+	ctx := context.Background()
+	errStop := errors.New("stop")
+
+	err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user := models.User{Username: "tx@mail.com", Password: "hash-tx", Role: "viewer"}
+		if err := repo.CreateUser(ctx, &user); err != nil {
+			return err
+		}
+		// Nested: ikut transaksi luar
+		return txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			return errStop
+		})
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("WithinTransaction error = %v, want errStop", err)
+	}
+	if _, err := repo.GetUserByUsername(ctx, "tx@mail.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("user after rollback: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWithinTransactionCommits(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	err := NewTransactionManager(db).WithinTransaction(ctx, func(ctx context.Context) error {
+		user := models.User{Username: "commit@mail.com", Password: "hash-commit", Role: "viewer"}
+		return repo.CreateUser(ctx, &user)
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction: %v", err)
+	}
+	if _, err := repo.GetUserByUsername(ctx, "commit@mail.com"); err != nil {
+		t.Errorf("user after commit: %v", err)
+	}
+}
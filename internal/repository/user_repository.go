@@ -1,21 +1,36 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"project/internal/models"
+	"project/pkg/logger"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict dikembalikan ketika versi user di database sudah berubah
+// sejak dibaca (optimistic concurrency control)
+var ErrVersionConflict = errors.New("user version conflict")
+
+// ErrRoleNotFound dikembalikan ketika role yang akan dikaitkan ke user tidak ada
+var ErrRoleNotFound = errors.New("role not found")
+
+// log adalah logger komponen "repository"
+var log = logger.New("repository")
+
 type UserRepository interface {
-	GetAllUsers(page, limit int, sort string, filter map[string]interface{}) ([]models.User, int64, error)
-	GetUserByID(id string) (models.User, error)
-	GetUserByUsername(username string) (models.User, error)
-	CreateUser(user *models.User) error
+	GetAllUsers(ctx context.Context, page, limit int, sort string, filter map[string]interface{}) ([]models.User, int64, error)
+	GetUserByID(ctx context.Context, id string) (models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
 	// UpdateUser(user *models.User) error
-	UpdateUser(id string, user *models.User) error
-	DeleteUser(id string) error
-	FindByID(id string) (*models.User, error)
+	UpdateUser(ctx context.Context, id string, user *models.User) error
+	UpdateUserFields(ctx context.Context, id string, version uint, fields map[string]interface{}) error
+	DeleteUser(ctx context.Context, id string, version uint) error
+	FindByID(ctx context.Context, id string) (*models.User, error)
+	AssignRoles(ctx context.Context, user *models.User, roleNames []string) error
 }
 
 type userRepository struct {
@@ -26,14 +41,19 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db}
 }
 
+// conn memakai transaksi dari ctx (lihat TransactionManager) jika ada
+func (r *userRepository) conn(ctx context.Context) *gorm.DB {
+	return DB(ctx, r.db)
+}
+
 // GetAllUsers memanggil database untuk mendapatkan semua pengguna
-func (r *userRepository) GetAllUsers(page, limit int, sort string, filter map[string]interface{}) ([]models.User, int64, error) {
+func (r *userRepository) GetAllUsers(ctx context.Context, page, limit int, sort string, filter map[string]interface{}) ([]models.User, int64, error) {
 	var users []models.User
 	var total int64
 
 	// query := r.db.Model(&models.User{})
 	// Membuat query dengan filter dan sort
-	query := r.db.Model(&models.User{}).Preload("Roles").Preload("Permissions")
+	query := r.conn(ctx).Model(&models.User{}).Preload("Roles").Preload("Permissions")
 
 	// Apply filtering
 	for key, value := range filter {
@@ -45,68 +65,152 @@ func (r *userRepository) GetAllUsers(page, limit int, sort string, filter map[st
 		query = query.Order(sort)
 	}
 
-	// Apply pagination
-	query = query.Offset((page - 1) * limit).Limit(limit)
-
-	// Count total number of records
-	query.Count(&total)
+	// Count total number of records (sebelum pagination, agar total bukan jumlah di halaman ini)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, translateError(err)
+	}
 
-	// Get users
-	if err := query.Find(&users).Error; err != nil {
-		return nil, 0, err
+	// Apply pagination and get users
+	if err := query.Offset((page - 1) * limit).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, translateError(err)
 	}
 
 	return users, total, nil
 }
 
 // GetUserByID memanggil database untuk mendapatkan pengguna berdasarkan ID
-func (r *userRepository) GetUserByID(id string) (models.User, error) {
+func (r *userRepository) GetUserByID(ctx context.Context, id string) (models.User, error) {
 	var user models.User
 	parsedID, err := uuid.Parse(id)
 	if err != nil {
 		return user, err
 	}
 
-	if err := r.db.First(&user, "id = ?", parsedID).Error; err != nil {
-		return user, err
+	if err := r.conn(ctx).First(&user, "id = ?", parsedID).Error; err != nil {
+		return user, translateError(err)
 	}
 
 	return user, nil
 }
 
-func (r *userRepository) GetUserByUsername(username string) (models.User, error) {
+func (r *userRepository) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
 	var user models.User
-	err := r.db.Where("username = ?", username).First(&user).Error
-	return user, err
+	err := r.conn(ctx).Where("username = ?", username).First(&user).Error
+	return user, translateError(err)
 }
 
 // CreateUser menambahkan pengguna baru ke database
-func (r *userRepository) CreateUser(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) CreateUser(ctx context.Context, user *models.User) error {
+	return translateError(r.conn(ctx).Create(user).Error)
 }
 
-// FindByID retrieves a user by ID.
-func (r *userRepository) FindByID(id string) (*models.User, error) {
+// FindByID retrieves a user by ID beserta Roles (urut nama), untuk pemeriksaan
+// akses. ID yang bukan UUID dianggap tidak ditemukan.
+func (r *userRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
 	var user models.User
-	if err := r.db.First(&user, "id = ?", id).Error; err != nil {
-		return nil, err
+	err = r.conn(ctx).Preload("Roles", func(db *gorm.DB) *gorm.DB {
+		return db.Order("roles.name")
+	}).First(&user, "id = ?", parsedID).Error
+	if err != nil {
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
 
-func (r *userRepository) UpdateUser(id string, user *models.User) error {
-	if err := r.db.Model(&models.User{}).Where("id = ?", id).Updates(user).Error; err != nil {
+// UpdateUser menyimpan perubahan user hanya jika user.Version masih sama dengan
+// versi di database, lalu menaikkan versinya
+func (r *userRepository) UpdateUser(ctx context.Context, id string, user *models.User) error {
+	currentVersion := user.Version
+	user.Version = currentVersion + 1
+
+	result := r.conn(ctx).Model(&models.User{}).Where("id = ? AND version = ?", id, currentVersion).Updates(user)
+	if result.Error != nil {
+		user.Version = currentVersion
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		user.Version = currentVersion
+		log.DebugContext(ctx, "user update skipped, version changed", "user_id", id, "expected_version", currentVersion)
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// UpdateUserFields memperbarui hanya kolom yang ada di fields.
+// Berbeda dengan UpdateUser, zero value ("" / 0 / false) tetap ditulis ke database.
+// Perubahan hanya diterapkan jika versi user masih sama dengan version.
+func (r *userRepository) UpdateUserFields(ctx context.Context, id string, version uint, fields map[string]interface{}) error {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
 		return err
 	}
+
+	updates := make(map[string]interface{}, len(fields)+1)
+	for column, value := range fields {
+		updates[column] = value
+	}
+	updates["version"] = gorm.Expr("version + 1")
+
+	result := r.conn(ctx).Model(&models.User{}).Where("id = ? AND version = ?", parsedID, version).Updates(updates)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		log.DebugContext(ctx, "user update skipped, version changed", "user_id", id, "expected_version", version)
+		return ErrVersionConflict
+	}
 	return nil
 }
 
-// DeleteUser menghapus pengguna berdasarkan ID
-func (r *userRepository) DeleteUser(id string) error {
+// DeleteUser menghapus pengguna berdasarkan ID jika versinya masih sama dengan version
+func (r *userRepository) DeleteUser(ctx context.Context, id string, version uint) error {
 	parsedID, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
 
-	return r.db.Delete(&models.User{}, "id = ?", parsedID).Error
+	result := r.conn(ctx).Delete(&models.User{}, "id = ? AND version = ?", parsedID, version)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		log.DebugContext(ctx, "user delete skipped, version changed", "user_id", id, "expected_version", version)
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// AssignRoles mengganti daftar role user dengan role bernama roleNames; nama
+// yang sama boleh muncul lebih dari sekali. Mengembalikan ErrRoleNotFound jika
+// salah satu role belum ada.
+func (r *userRepository) AssignRoles(ctx context.Context, user *models.User, roleNames []string) error {
+	roleNames = uniqueNames(roleNames)
+	var roles []models.Role
+	if err := r.conn(ctx).Where("name IN ?", roleNames).Find(&roles).Error; err != nil {
+		return translateError(err)
+	}
+	if len(roles) != len(roleNames) {
+		log.DebugContext(ctx, "some roles do not exist", "roles", roleNames, "found", len(roles))
+		return ErrRoleNotFound
+	}
+
+	return translateError(r.conn(ctx).Model(user).Association("Roles").Replace(&roles))
+}
+
+// uniqueNames mengembalikan names tanpa duplikat, urutan pertama dipertahankan
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
 }
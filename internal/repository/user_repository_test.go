@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/testutil"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func newTestUserRepository(t *testing.T) (UserRepository, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	return NewUserRepository(db), db
+}
+
+func createTestUser(t *testing.T, repo UserRepository, username string) models.User {
+	t.Helper()
+	user := models.User{Username: username, Password: "hash-" + username, Role: "viewer"}
+	if err := repo.CreateUser(context.Background(), &user); err != nil {
+		t.Fatalf("CreateUser(%s): %v", username, err)
+	}
+	return user
+}
+
+func TestUserRepositoryCreateAndGet(t *testing.T) {
+	repo, _ := newTestUserRepository(t)
+	ctx := context.Background()
+	user := createTestUser(t, repo, "get@mail.com")
+
+	got, err := repo.GetUserByID(ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if got.Username != user.Username || got.Version != 1 {
+		t.Errorf("GetUserByID = %s version %d, want %s version 1", got.Username, got.Version, user.Username)
+	}
+
+	if _, err := repo.GetUserByUsername(ctx, "missing@mail.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUserByUsername(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUserRepositoryDuplicateUsername(t *testing.T) {
+	repo, _ := newTestUserRepository(t)
+	createTestUser(t, repo, "dup@mail.com")
+
+	user := models.User{Username: "dup@mail.com", Password: "other-hash", Role: "viewer"}
+	err := repo.CreateUser(context.Background(), &user)
+
+	var dbErr *DBError
+	if !errors.As(err, &dbErr) || !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("CreateUser(duplicate) error = %v, want DBError ErrDuplicate", err)
+	}
+	if dbErr.Column != "username" {
+		t.Errorf("DBError.Column = %q, want username", dbErr.Column)
+	}
+}
+
+func TestUserRepositoryUpdateVersion(t *testing.T) {
+	repo, _ := newTestUserRepository(t)
+	ctx := context.Background()
+	user := createTestUser(t, repo, "update@mail.com")
+	stale := user
+
+	user.Role = "admin"
+	if err := repo.UpdateUser(ctx, user.ID.String(), &user); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if user.Version != 2 {
+		t.Errorf("version after UpdateUser = %d, want 2", user.Version)
+	}
+
+	stale.Role = "superadmin"
+	if err := repo.UpdateUser(ctx, stale.ID.String(), &stale); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("UpdateUser(stale) error = %v, want ErrVersionConflict", err)
+	}
+	if stale.Version != 1 {
+		t.Errorf("stale version after conflict = %d, want 1 (unchanged)", stale.Version)
+	}
+
+	if err := repo.UpdateUserFields(ctx, user.ID.String(), 1, map[string]interface{}{"role": "viewer"}); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("UpdateUserFields(stale) error = %v, want ErrVersionConflict", err)
+	}
+	if err := repo.UpdateUserFields(ctx, user.ID.String(), 2, map[string]interface{}{"role": "viewer"}); err != nil {
+		t.Fatalf("UpdateUserFields: %v", err)
+	}
+	got, err := repo.GetUserByID(ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if got.Role != "viewer" || got.Version != 3 {
+		t.Errorf("after UpdateUserFields role=%s version=%d, want viewer version 3", got.Role, got.Version)
+	}
+}
+
+func TestUserRepositoryDelete(t *testing.T) {
+	repo, _ := newTestUserRepository(t)
+	ctx := context.Background()
+	user := createTestUser(t, repo, "delete@mail.com")
+
+	if err := repo.DeleteUser(ctx, user.ID.String(), 7); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("DeleteUser(wrong version) error = %v, want ErrVersionConflict", err)
+	}
+	if err := repo.DeleteUser(ctx, user.ID.String(), user.Version); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := repo.GetUserByID(ctx, user.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUserByID after delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUserRepositoryGetAllUsers(t *testing.T) {
+	repo, _ := newTestUserRepository(t)
+	ctx := context.Background()
+	for _, username := range []string{"c@mail.com", "a@mail.com", "b@mail.com"} {
+		createTestUser(t, repo, username)
+	}
+
+	users, total, err := repo.GetAllUsers(ctx, 2, 2, "username", nil)
+	if err != nil {
+		t.Fatalf("GetAllUsers: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("total = %d, want 3", total)
+	}
+	if len(users) != 1 || users[0].Username != "c@mail.com" {
+		t.Errorf("page 2 = %v, want [c@mail.com]", usernames(users))
+	}
+
+	users, total, err = repo.GetAllUsers(ctx, 1, 10, "", map[string]interface{}{"username": "a@mail.com"})
+	if err != nil {
+		t.Fatalf("GetAllUsers(filter): %v", err)
+	}
+	if total != 1 || len(users) != 1 || users[0].Username != "a@mail.com" {
+		t.Errorf("filtered = %v (total %d), want [a@mail.com]", usernames(users), total)
+	}
+}
+
+func TestUserRepositoryAssignRoles(t *testing.T) {
+	repo, db := newTestUserRepository(t)
+	ctx := context.Background()
+	testutil.CreateRoles(t, db, "admin", "viewer")
+	user := createTestUser(t, repo, "roles@mail.com")
+
+	// Nama yang sama lebih dari sekali tidak dianggap role yang hilang
+	if err := repo.AssignRoles(ctx, &user, []string{"admin", "admin", "viewer"}); err != nil {
+		t.Fatalf("AssignRoles: %v", err)
+	}
+	if err := repo.AssignRoles(ctx, &user, []string{"viewer"}); err != nil {
+		t.Fatalf("AssignRoles(replace): %v", err)
+	}
+	if err := repo.AssignRoles(ctx, &user, []string{"viewer", "missing"}); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("AssignRoles(missing) error = %v, want ErrRoleNotFound", err)
+	}
+
+	var saved models.User
+	if err := db.Preload("Roles").First(&saved, "id = ?", user.ID).Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if len(saved.Roles) != 1 || saved.Roles[0].Name != "viewer" {
+		t.Errorf("roles = %v, want [viewer]", saved.Roles)
+	}
+}
+
+func usernames(users []models.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"project/internal/models"
+	"time"
+)
+
+// auditTargetUser - target_type untuk perubahan pada user
+const auditTargetUser = "user"
+
+// auditedUserService membungkus UserService dan mencatat setiap perubahan ke
+// audit log. Data sebelum perubahan, perubahan dan catatannya berjalan dalam satu
+// transaksi (AuditService.WithinTransaction); jika catatan gagal ditulis,
+// perubahan ikut dibatalkan.
+type auditedUserService struct {
+	UserService
+	audit AuditService
+}
+
+// NewAuditedUserService membungkus next sehingga create, update, patch, delete,
+// disable dan reset password tercatat di audit log
+func NewAuditedUserService(next UserService, audit AuditService) UserService {
+	return &auditedUserService{next, audit}
+}
+
+func (s *auditedUserService) CreateUser(ctx context.Context, user *models.User) error {
+	return s.audit.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.UserService.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return s.record(ctx, AuditUserCreate, user.ID.String(), nil, userSnapshot(*user))
+	})
+}
+
+func (s *auditedUserService) CreateUserWithRoles(ctx context.Context, user *models.User, roleNames []string) error {
+	return s.audit.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.UserService.CreateUserWithRoles(ctx, user, roleNames); err != nil {
+			return err
+		}
+		after := userSnapshot(*user)
+		after["roles"] = roleNames
+		return s.record(ctx, AuditUserCreate, user.ID.String(), nil, after)
+	})
+}
+
+func (s *auditedUserService) UpdateUser(ctx context.Context, id string, user *models.User) error {
+	return s.audit.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.UserService.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.UserService.UpdateUser(ctx, id, user); err != nil {
+			return err
+		}
+		return s.record(ctx, AuditUserUpdate, id, userSnapshot(before), userSnapshot(*user))
+	})
+}
+
+func (s *auditedUserService) PatchUser(ctx context.Context, id string, version uint, fields map[string]interface{}) (models.User, error) {
+	return s.patch(ctx, AuditUserPatch, id, func(ctx context.Context) (models.User, error) {
+		return s.UserService.PatchUser(ctx, id, version, fields)
+	})
+}
+
+func (s *auditedUserService) DeleteUser(ctx context.Context, id string, version uint) error {
+	return s.audit.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.UserService.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.UserService.DeleteUser(ctx, id, version); err != nil {
+			return err
+		}
+		return s.record(ctx, AuditUserDelete, id, userSnapshot(before), nil)
+	})
+}
+
+func (s *auditedUserService) DisableUser(ctx context.Context, id string) error {
+	_, err := s.patch(ctx, AuditUserDisable, id, func(ctx context.Context) (models.User, error) {
+		if err := s.UserService.DisableUser(ctx, id); err != nil {
+			return models.User{}, err
+		}
+		return s.UserService.GetUserByID(ctx, id)
+	})
+	return err
+}
+
+func (s *auditedUserService) ResetPassword(ctx context.Context, id string, password string) error {
+	_, err := s.patch(ctx, AuditUserResetPassword, id, func(ctx context.Context) (models.User, error) {
+		if err := s.UserService.ResetPassword(ctx, id, password); err != nil {
+			return models.User{}, err
+		}
+		return s.UserService.GetUserByID(ctx, id)
+	})
+	return err
+}
+
+// patch membaca user, menjalankan change lalu mencatat selisihnya dengan user
+// yang dikembalikan change, semuanya dalam satu transaksi
+func (s *auditedUserService) patch(ctx context.Context, action, id string, change func(ctx context.Context) (models.User, error)) (models.User, error) {
+	var after models.User
+	err := s.audit.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.UserService.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if after, err = change(ctx); err != nil {
+			return err
+		}
+		return s.record(ctx, action, id, userSnapshot(before), userSnapshot(after))
+	})
+	return after, err
+}
+
+func (s *auditedUserService) record(ctx context.Context, action, id string, before, after map[string]interface{}) error {
+	if err := s.audit.Record(ctx, action, auditTargetUser, id, diffAudit(before, after)); err != nil {
+		log.ErrorContext(ctx, "failed to record audit log, change rolled back", "action", action, "target_id", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+// userSnapshot mengambil field user yang dicatat di audit log.
+// Password (hash) hanya dipakai untuk mendeteksi perubahan dan disamarkan di diff.
+func userSnapshot(user models.User) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"username": user.Username,
+		"password": user.Password,
+		"role":     user.Role,
+		"version":  user.Version,
+	}
+	if user.DisabledAt != nil {
+		snapshot["disabled_at"] = user.DisabledAt.UTC().Format(time.RFC3339)
+	}
+	if user.Locale != "" {
+		snapshot["locale"] = user.Locale
+	}
+	return snapshot
+}
+
+// auditedRoleService membungkus RoleService dan mencatat grant/revoke ke audit log
+// dalam transaksi yang sama dengan perubahannya
+type auditedRoleService struct {
+	next  RoleService
+	audit AuditService
+}
+
+// NewAuditedRoleService membungkus next sehingga grant dan revoke role tercatat di audit log
+func NewAuditedRoleService(next RoleService, audit AuditService) RoleService {
+	return &auditedRoleService{next, audit}
+}
+
+func (s *auditedRoleService) GrantRole(ctx context.Context, userID string, roleName string) error {
+	return s.audit.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.next.GrantRole(ctx, userID, roleName); err != nil {
+			return err
+		}
+		return s.record(ctx, AuditRoleGrant, userID, AuditChange{To: roleName})
+	})
+}
+
+func (s *auditedRoleService) RevokeRole(ctx context.Context, userID string, roleName string) error {
+	return s.audit.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.next.RevokeRole(ctx, userID, roleName); err != nil {
+			return err
+		}
+		return s.record(ctx, AuditRoleRevoke, userID, AuditChange{From: roleName})
+	})
+}
+
+func (s *auditedRoleService) record(ctx context.Context, action, userID string, change AuditChange) error {
+	changes := map[string]AuditChange{"roles": change}
+	if err := s.audit.Record(ctx, action, auditTargetUser, userID, changes); err != nil {
+		log.ErrorContext(ctx, "failed to record audit log, change rolled back", "action", action, "target_id", userID, "error", err)
+		return err
+	}
+	return nil
+}
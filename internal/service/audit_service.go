@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project/internal/models"
+	"project/internal/repository"
+	"project/pkg/database"
+	"project/pkg/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GenesisHash adalah prev_hash untuk baris audit pertama
+var GenesisHash = strings.Repeat("0", 64)
+
+// redactedValue menggantikan nilai field rahasia di diff audit
+const redactedValue = "[REDACTED]"
+
+// redactedFields - Field yang nilainya tidak pernah disimpan di audit log
+var redactedFields = map[string]bool{
+	"password": true,
+}
+
+// Aksi yang dicatat di audit log
+const (
+	AuditUserCreate        = "user.create"
+	AuditUserUpdate        = "user.update"
+	AuditUserPatch         = "user.patch"
+	AuditUserDelete        = "user.delete"
+	AuditUserDisable       = "user.disable"
+	AuditUserResetPassword = "user.reset_password"
+	AuditRoleGrant         = "role.grant"
+	AuditRoleRevoke        = "role.revoke"
+)
+
+// Actor - Pelaku perubahan yang dicatat di audit log
+type Actor struct {
+	ID       string
+	Username string
+	IP       string
+}
+
+// actorKey - Key context untuk Actor
+type actorKey struct{}
+
+// WithActor menyimpan pelaku request di ctx (diisi oleh middleware JWT atau CLI)
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext mengembalikan pelaku dari ctx (kosong jika tidak ada)
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// AuditChange - Nilai field sebelum dan sesudah perubahan
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditVerification - Hasil pemeriksaan rantai hash audit log
+type AuditVerification struct {
+	Valid bool `json:"valid"`
+	// Checked adalah jumlah baris yang diperiksa
+	Checked int64 `json:"checked"`
+	// Head adalah hash baris terakhir; simpan di luar database untuk mendeteksi
+	// penghapusan baris paling akhir
+	Head string `json:"head"`
+	// BrokenAt adalah id baris pertama yang tidak cocok
+	BrokenAt *uint  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type AuditService interface {
+	// Record menambahkan satu catatan; pelaku, IP dan request ID diambil dari ctx
+	Record(ctx context.Context, action, targetType, targetID string, changes map[string]AuditChange) error
+	// WithinTransaction menjalankan fn dalam satu transaksi di primary. Perubahan dan
+	// Record yang dipanggil dengan ctx dari fn di-commit atau di-rollback bersama;
+	// penambahan catatan dalam proses ini menunggu sampai transaksi selesai.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	List(ctx context.Context, filter repository.AuditFilter, page, limit int) ([]models.AuditLog, int64, error)
+	// Verify memeriksa seluruh rantai hash dari baris pertama
+	Verify(ctx context.Context) (AuditVerification, error)
+}
+
+type auditService struct {
+	repo      repository.AuditRepository
+	txManager repository.TransactionManager
+	// mu mengurutkan penambahan baris dalam satu proses; antar instance, kolom
+	// prev_hash yang unik menolak cabang rantai dan Record mencoba ulang
+	mu sync.Mutex
+}
+
+func NewAuditService(repo repository.AuditRepository, txManager repository.TransactionManager) AuditService {
+	return &auditService{repo: repo, txManager: txManager}
+}
+
+// auditAppendAttempts - Jumlah percobaan jika baris terakhir berubah saat menambah
+const auditAppendAttempts = 5
+
+// auditLockKey - Key context yang menandai mu sudah dipegang oleh WithinTransaction
+type auditLockKey struct{}
+
+func (s *auditService) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(auditLockKey{}) != nil {
+		return fn(ctx)
+	}
+
+	// mu dipegang sampai commit agar catatan berikutnya membaca baris terakhir yang
+	// sudah tersimpan; data sebelum perubahan juga dibaca dari primary
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx = context.WithValue(database.UsePrimary(ctx), auditLockKey{}, true)
+	return s.txManager.WithinTransaction(ctx, fn)
+}
+
+func (s *auditService) Record(ctx context.Context, action, targetType, targetID string, changes map[string]AuditChange) error {
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	actor := ActorFromContext(ctx)
+	entry := models.AuditLog{
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+		Changes:       models.JSONText(changesJSON),
+		IP:            actor.IP,
+		RequestID:     logger.RequestID(ctx),
+	}
+
+	// Di dalam WithinTransaction, mu sudah dipegang sampai transaksi selesai
+	held := ctx.Value(auditLockKey{}) != nil
+	if !held {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	// Rantai selalu dibaca dari primary, bukan read replica
+	ctx = database.UsePrimary(ctx)
+	for attempt := 1; ; attempt++ {
+		var prevHash string
+		err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			last, ok, err := s.repo.Last(ctx)
+			if err != nil {
+				return err
+			}
+			prevHash = GenesisHash
+			if ok {
+				prevHash = last.Hash
+			}
+
+			entry.ID = 0
+			entry.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+			entry.PrevHash = prevHash
+			entry.Hash = auditHash(entry)
+			return s.repo.Create(ctx, &entry)
+		})
+		if err == nil {
+			return nil
+		}
+
+		// Instance lain menambah baris lebih dulu (prev_hash bentrok dengan unique
+		// constraint): ulangi dengan baris terakhir yang baru. Transaksi pemanggil
+		// tidak bisa dilanjutkan setelah gagal, jadi error dikembalikan dan
+		// perubahannya ikut di-rollback.
+		if held || attempt >= auditAppendAttempts || !errors.Is(err, repository.ErrDuplicate) {
+			return err
+		}
+		last, ok, lastErr := s.repo.Last(ctx)
+		if lastErr != nil || !ok || last.Hash == prevHash {
+			return err
+		}
+	}
+}
+
+func (s *auditService) List(ctx context.Context, filter repository.AuditFilter, page, limit int) ([]models.AuditLog, int64, error) {
+	return s.repo.List(ctx, filter, page, limit)
+}
+
+func (s *auditService) Verify(ctx context.Context) (AuditVerification, error) {
+	result := AuditVerification{Valid: true, Head: GenesisHash}
+	err := s.repo.Walk(database.UsePrimary(ctx), 500, func(entries []models.AuditLog) error {
+		for _, entry := range entries {
+			if !result.Valid {
+				return nil
+			}
+			result.Checked++
+			switch {
+			case entry.PrevHash != result.Head:
+				result.Reason = "prev_hash does not match the previous entry"
+			case entry.Hash != auditHash(entry):
+				result.Reason = "hash does not match the entry content"
+			default:
+				result.Head = entry.Hash
+				continue
+			}
+			id := entry.ID
+			result.Valid = false
+			result.BrokenAt = &id
+		}
+		return nil
+	})
+	if err != nil {
+		return AuditVerification{}, err
+	}
+	return result, nil
+}
+
+// auditHash menghitung SHA-256 dari isi baris dan prev_hash.
+// created_at dipakai dalam milidetik UTC agar hasilnya sama di semua driver.
+func auditHash(entry models.AuditLog) string {
+	content, _ := json.Marshal(struct {
+		PrevHash      string `json:"prev_hash"`
+		ActorID       string `json:"actor_id"`
+		ActorUsername string `json:"actor_username"`
+		Action        string `json:"action"`
+		TargetType    string `json:"target_type"`
+		TargetID      string `json:"target_id"`
+		Changes       string `json:"changes"`
+		IP            string `json:"ip"`
+		RequestID     string `json:"request_id"`
+		CreatedAt     int64  `json:"created_at"`
+	}{
+		PrevHash:      entry.PrevHash,
+		ActorID:       entry.ActorID,
+		ActorUsername: entry.ActorUsername,
+		Action:        entry.Action,
+		TargetType:    entry.TargetType,
+		TargetID:      entry.TargetID,
+		Changes:       string(entry.Changes),
+		IP:            entry.IP,
+		RequestID:     entry.RequestID,
+		CreatedAt:     entry.CreatedAt.UnixMilli(),
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// diffAudit membandingkan dua snapshot dan mengembalikan field yang berubah.
+// Nilai field rahasia (password) diganti redactedValue.
+func diffAudit(before, after map[string]interface{}) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+	for field, from := range before {
+		if to, ok := after[field]; !ok || fmt.Sprint(from) != fmt.Sprint(to) {
+			changes[field] = AuditChange{From: redact(field, from), To: redact(field, after[field])}
+		}
+	}
+	for field, to := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = AuditChange{From: nil, To: redact(field, to)}
+		}
+	}
+	return changes
+}
+
+func redact(field string, value interface{}) interface{} {
+	if value != nil && redactedFields[field] {
+		return redactedValue
+	}
+	return value
+}
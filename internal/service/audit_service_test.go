@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/repository"
+	"project/internal/testutil"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func newTestAuditedUserService(t *testing.T) (UserService, AuditService, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	txManager := repository.NewTransactionManager(db)
+	audit := NewAuditService(repository.NewAuditRepository(db), txManager)
+	users := NewUserService(repository.NewUserRepository(db), txManager)
+	return NewAuditedUserService(users, audit), audit, db
+}
+
+func TestAuditedUserServiceRecordsChain(t *testing.T) {
+	svc, audit, _ := newTestAuditedUserService(t)
+	ctx := WithActor(context.Background(), Actor{ID: "cli", Username: "tester"})
+
+	user := models.User{Username: "audited@mail.com", Password: "hash-audited", Role: "viewer"}
+	if err := svc.CreateUser(ctx, &user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := svc.PatchUser(ctx, user.ID.String(), 0, map[string]interface{}{"role": "admin"}); err != nil {
+		t.Fatalf("PatchUser: %v", err)
+	}
+
+	entries, total, err := audit.List(ctx, repository.AuditFilter{TargetID: user.ID.String()}, 1, 10)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("audit entries = %d, want 2", total)
+	}
+	if entries[0].Action != AuditUserPatch || entries[0].ActorUsername != "tester" {
+		t.Errorf("newest entry = %s by %s, want %s by tester", entries[0].Action, entries[0].ActorUsername, AuditUserPatch)
+	}
+
+	verification, err := audit.Verify(ctx)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !verification.Valid || verification.Checked != 2 {
+		t.Errorf("Verify = valid %v checked %d, want valid with 2 entries", verification.Valid, verification.Checked)
+	}
+}
+
+func TestAuditedUserServiceRollsBackWhenAuditFails(t *testing.T) {
+	svc, _, db := newTestAuditedUserService(t)
+	ctx := context.Background()
+
+	user := models.User{Username: "keep@mail.com", Password: "hash-keep", Role: "viewer"}
+	if err := svc.CreateUser(ctx, &user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	// Semua insert audit gagal
+	if err := db.Exec("CREATE TRIGGER audit_logs_fail BEFORE INSERT ON audit_logs BEGIN SELECT RAISE(ABORT, 'audit unavailable'); END").Error; err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	if _, err := svc.PatchUser(ctx, user.ID.String(), 0, map[string]interface{}{"role": "admin"}); err == nil {
+		t.Fatal("PatchUser succeeded while audit log is failing")
+	}
+	got, err := svc.GetUserByID(ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if got.Role != "viewer" || got.Version != 1 {
+		t.Errorf("user after failed audit = role %s version %d, want unchanged viewer version 1", got.Role, got.Version)
+	}
+
+	other := models.User{Username: "gone@mail.com", Password: "hash-gone", Role: "viewer"}
+	if err := svc.CreateUser(ctx, &other); err == nil {
+		t.Fatal("CreateUser succeeded while audit log is failing")
+	}
+	if _, err := svc.GetUserByUsername(ctx, other.Username); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("GetUserByUsername after failed audit error = %v, want ErrUserNotFound", err)
+	}
+}
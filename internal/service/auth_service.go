@@ -0,0 +1,28 @@
+package service
+
+import (
+	"project/internal/models"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// TokenTTL - Masa berlaku default token JWT
+const TokenTTL = 72 * time.Hour
+
+// GenerateToken membuat token JWT untuk user dengan masa berlaku ttl
+func GenerateToken(jwtSecret string, user models.User, ttl time.Duration) (string, error) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["sub"] = user.ID
+	claims["username"] = user.Username
+	claims["role"] = user.Role
+	claims["permissions"] = user.Permissions
+	// Bahasa pilihan user; jika ada, dipakai untuk respons menggantikan Accept-Language
+	if user.Locale != "" {
+		claims["locale"] = user.Locale
+	}
+	claims["exp"] = time.Now().Add(ttl).Unix()
+
+	return token.SignedString([]byte(jwtSecret))
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"project/internal/repository"
+)
+
+// Kind mengelompokkan error aplikasi; handler memetakan setiap Kind ke satu status HTTP
+type Kind int
+
+const (
+	// KindInternal - error yang tidak diharapkan (500); detailnya tidak dikirim ke client
+	KindInternal Kind = iota
+	// KindValidation - input tidak valid (400); Fields berisi kode pesan per field
+	KindValidation
+	// KindUnauthorized - kredensial atau token tidak valid (401)
+	KindUnauthorized
+	// KindForbidden - pelaku tidak boleh melakukan aksi ini (403)
+	KindForbidden
+	// KindNotFound - data yang diminta tidak ada (404)
+	KindNotFound
+	// KindConflict - bertentangan dengan data yang sudah ada (409)
+	KindConflict
+	// KindPreconditionFailed - data sudah diubah sejak terakhir dibaca (412)
+	KindPreconditionFailed
+	// KindUnprocessable - input valid tetapi melanggar aturan data, contoh relasi yang tidak ada (422)
+	KindUnprocessable
+	// KindUnavailable - dependency (database) sedang tidak bisa dipakai; client boleh mencoba lagi (503)
+	KindUnavailable
+)
+
+// Error adalah error aplikasi dengan Code yang stabil untuk client (contoh
+// "user_not_found"). Message aman ditampilkan ke client; Err (jika ada) hanya untuk log.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Fields berisi kode pesan per field (JSON name), contoh "required"; diterjemahkan
+	// handler lewat katalog i18n "field.<kode>"
+	Fields map[string]string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Err.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound membuat error KindNotFound
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict membuat error KindConflict
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Validation membuat error KindValidation dengan pesan per field
+func Validation(code, message string, fields map[string]string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+// Forbidden membuat error KindForbidden
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// Unauthorized membuat error KindUnauthorized
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Unprocessable membuat error KindUnprocessable dengan pesan per field
+func Unprocessable(code, message string, fields map[string]string) *Error {
+	return &Error{Kind: KindUnprocessable, Code: code, Message: message, Fields: fields}
+}
+
+// Error yang dikembalikan service; bandingkan dengan errors.Is
+var (
+	// ErrUserNotFound - User tidak ada (atau sudah dihapus)
+	ErrUserNotFound = &Error{Kind: KindNotFound, Code: "user_not_found", Message: "User not found", Err: repository.ErrNotFound}
+	// ErrUsernameTaken - Username sudah dipakai user lain
+	ErrUsernameTaken = &Error{Kind: KindConflict, Code: "username_taken", Message: "Username is already taken", Fields: map[string]string{"username": "taken"}}
+	// ErrRoleNotFound - Salah satu role yang diminta belum ada
+	ErrRoleNotFound = &Error{Kind: KindNotFound, Code: "role_not_found", Message: "Role not found", Err: repository.ErrRoleNotFound}
+	// ErrVersionConflict - User sudah diubah oleh request lain sejak terakhir dibaca
+	ErrVersionConflict = &Error{Kind: KindPreconditionFailed, Code: "version_conflict", Message: "User has been modified by another request", Err: repository.ErrVersionConflict}
+	// ErrLastRole - Role yang dicabut adalah satu-satunya role user
+	ErrLastRole = Conflict("last_role", "Cannot revoke the only role of a user; grant another role first")
+	// ErrUserDisabled - User sudah dinonaktifkan dan tidak boleh login
+	ErrUserDisabled = Forbidden("user_disabled", "Account is disabled")
+	// ErrInvalidCredentials - Username atau password salah
+	ErrInvalidCredentials = Unauthorized("invalid_credentials", "Invalid username or password")
+)
+
+// translateError mengubah error repository yang dikenal menjadi error service.
+// ErrNotFound dianggap user tidak ditemukan; service lain memeriksanya sendiri lebih dulu.
+func translateError(err error) error {
+	var column string
+	if dbErr := (*repository.DBError)(nil); errors.As(err, &dbErr) {
+		column = dbErr.Column
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, repository.ErrVersionConflict):
+		return ErrVersionConflict
+	case errors.Is(err, repository.ErrRoleNotFound):
+		return ErrRoleNotFound
+	case errors.Is(err, repository.ErrDuplicate):
+		if column == "username" {
+			return ErrUsernameTaken
+		}
+		return &Error{Kind: KindConflict, Code: "duplicate", Message: "Resource already exists", Fields: columnField(column, "taken"), Err: err}
+	case errors.Is(err, repository.ErrForeignKey):
+		return &Error{Kind: KindUnprocessable, Code: "invalid_reference", Message: "Referenced resource does not exist or is still in use", Err: err}
+	case errors.Is(err, repository.ErrNotNull):
+		return &Error{Kind: KindUnprocessable, Code: "missing_value", Message: "A required value is missing", Fields: columnField(column, "required"), Err: err}
+	case errors.Is(err, repository.ErrUnavailable):
+		return &Error{Kind: KindUnavailable, Code: "service_unavailable", Message: "Service is temporarily unavailable, try again later", Err: err}
+	}
+	return err
+}
+
+// columnField membentuk Fields untuk satu kolom; nil jika kolomnya tidak diketahui
+func columnField(column, code string) map[string]string {
+	if column == "" {
+		return nil
+	}
+	return map[string]string{column: code}
+}
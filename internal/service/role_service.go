@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/repository"
+)
+
+type RoleService interface {
+	GrantRole(ctx context.Context, userID string, roleName string) error
+	RevokeRole(ctx context.Context, userID string, roleName string) error
+}
+
+type roleService struct {
+	roleRepo  repository.RoleRepository
+	userRepo  repository.UserRepository
+	txManager repository.TransactionManager
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository, txManager repository.TransactionManager) RoleService {
+	return &roleService{roleRepo, userRepo, txManager}
+}
+
+// GrantRole memberikan role ke user. Akses dicek terhadap semua role user
+// (lihat models.User.RoleNames), jadi role baru langsung berlaku.
+func (s *roleService) GrantRole(ctx context.Context, userID string, roleName string) error {
+	user, role, err := s.resolve(ctx, userID, roleName)
+	if err != nil {
+		return err
+	}
+	if err := s.roleRepo.AddUserRole(ctx, user.ID, role.ID); err != nil {
+		return translateError(err)
+	}
+	log.InfoContext(ctx, "role granted", "user_id", user.ID, "role", roleName)
+	return nil
+}
+
+// RevokeRole mencabut role dari user. Jika role tersebut adalah role utama
+// (users.role), role utama diganti role lain yang masih dimiliki user dalam
+// transaksi yang sama; role terakhir tidak bisa dicabut (ErrLastRole).
+func (s *roleService) RevokeRole(ctx context.Context, userID string, roleName string) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, role, err := s.resolve(ctx, userID, roleName)
+		if err != nil {
+			return err
+		}
+		if err := s.roleRepo.RemoveUserRole(ctx, user.ID, role.ID); err != nil {
+			return translateError(err)
+		}
+
+		if user.Role == roleName {
+			remaining := user.RoleNames()[1:]
+			if len(remaining) == 0 {
+				return ErrLastRole
+			}
+			fields := map[string]interface{}{"role": remaining[0]}
+			if err := s.userRepo.UpdateUserFields(ctx, user.ID.String(), user.Version, fields); err != nil {
+				return translateError(err)
+			}
+			log.InfoContext(ctx, "primary role replaced", "user_id", user.ID, "role", remaining[0])
+		}
+
+		log.InfoContext(ctx, "role revoked", "user_id", user.ID, "role", roleName)
+		return nil
+	})
+}
+
+// resolve memastikan user (beserta Roles) dan role ada
+func (s *roleService) resolve(ctx context.Context, userID string, roleName string) (*models.User, models.Role, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, models.Role{}, translateError(err)
+	}
+
+	role, err := s.roleRepo.GetRoleByName(ctx, roleName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, models.Role{}, ErrRoleNotFound
+		}
+		return nil, models.Role{}, translateError(err)
+	}
+
+	return user, role, nil
+}
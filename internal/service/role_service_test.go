@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/repository"
+	"project/internal/testutil"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func newTestRoleService(t *testing.T) (RoleService, UserService, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	testutil.CreateRoles(t, db, "admin", "superadmin", "viewer")
+	userRepository := repository.NewUserRepository(db)
+	txManager := repository.NewTransactionManager(db)
+	roleService := NewRoleService(repository.NewRoleRepository(db), userRepository, txManager)
+	return roleService, NewUserService(userRepository, txManager), db
+}
+
+// userRoles mengembalikan role utama dan semua role user dari database
+func userRoles(t *testing.T, users UserService, id string) []string {
+	t.Helper()
+	user, err := users.FindUserByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("FindUserByID: %v", err)
+	}
+	return user.RoleNames()
+}
+
+func TestRoleServiceGrantAndRevoke(t *testing.T) {
+	roles, users, _ := newTestRoleService(t)
+	ctx := context.Background()
+
+	user := models.User{Username: "roles@mail.com", Password: "hash-roles", Role: "viewer"}
+	if err := users.CreateUserWithRoles(ctx, &user, []string{"viewer"}); err != nil {
+		t.Fatalf("CreateUserWithRoles: %v", err)
+	}
+	id := user.ID.String()
+
+	if err := roles.GrantRole(ctx, id, "superadmin"); err != nil {
+		t.Fatalf("GrantRole: %v", err)
+	}
+	if got, want := userRoles(t, users, id), []string{"viewer", "superadmin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("roles after grant = %v, want %v", got, want)
+	}
+
+	// Mencabut role utama memindahkan users.role ke role lain yang masih dimiliki
+	if err := roles.RevokeRole(ctx, id, "viewer"); err != nil {
+		t.Fatalf("RevokeRole(viewer): %v", err)
+	}
+	if got, want := userRoles(t, users, id), []string{"superadmin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("roles after revoking the primary role = %v, want %v", got, want)
+	}
+
+	// Role terakhir tidak bisa dicabut dan tidak ada yang berubah
+	if err := roles.RevokeRole(ctx, id, "superadmin"); !errors.Is(err, ErrLastRole) {
+		t.Fatalf("RevokeRole(last) error = %v, want ErrLastRole", err)
+	}
+	if got, want := userRoles(t, users, id), []string{"superadmin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("roles after refused revoke = %v, want %v", got, want)
+	}
+
+	if err := roles.GrantRole(ctx, id, "missing"); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("GrantRole(missing) error = %v, want ErrRoleNotFound", err)
+	}
+}
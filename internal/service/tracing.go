@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/repository"
+	"project/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer adalah tracer komponen "service"
+var tracer = tracing.Tracer("service")
+
+// startSpan membuat span child untuk satu pemanggilan service
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan mencatat error (selain data tidak ditemukan) lalu menutup span
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedUserService membungkus UserService dan membuat span untuk setiap method
+type tracedUserService struct {
+	next UserService
+}
+
+// NewTracedUserService membungkus next sehingga setiap pemanggilan tercatat sebagai span
+func NewTracedUserService(next UserService) UserService {
+	return &tracedUserService{next}
+}
+
+func (s *tracedUserService) GetAllUsers(ctx context.Context, page, limit int, sort string, filter map[string]interface{}) (users []models.User, total int64, err error) {
+	ctx, span := startSpan(ctx, "UserService.GetAllUsers", attribute.Int("page", page), attribute.Int("limit", limit))
+	defer func() { endSpan(span, err) }()
+	return s.next.GetAllUsers(ctx, page, limit, sort, filter)
+}
+
+func (s *tracedUserService) GetUserByID(ctx context.Context, id string) (user models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.GetUserByID", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+	return s.next.GetUserByID(ctx, id)
+}
+
+func (s *tracedUserService) GetUserByUsername(ctx context.Context, username string) (user models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.GetUserByUsername")
+	defer func() { endSpan(span, err) }()
+	return s.next.GetUserByUsername(ctx, username)
+}
+
+func (s *tracedUserService) CreateUser(ctx context.Context, user *models.User) (err error) {
+	ctx, span := startSpan(ctx, "UserService.CreateUser")
+	defer func() { endSpan(span, err) }()
+	return s.next.CreateUser(ctx, user)
+}
+
+func (s *tracedUserService) CreateUserWithRoles(ctx context.Context, user *models.User, roleNames []string) (err error) {
+	ctx, span := startSpan(ctx, "UserService.CreateUserWithRoles", attribute.StringSlice("roles", roleNames))
+	defer func() { endSpan(span, err) }()
+	return s.next.CreateUserWithRoles(ctx, user, roleNames)
+}
+
+func (s *tracedUserService) UpdateUser(ctx context.Context, id string, user *models.User) (err error) {
+	ctx, span := startSpan(ctx, "UserService.UpdateUser", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+	return s.next.UpdateUser(ctx, id, user)
+}
+
+func (s *tracedUserService) PatchUser(ctx context.Context, id string, version uint, fields map[string]interface{}) (user models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.PatchUser", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+	return s.next.PatchUser(ctx, id, version, fields)
+}
+
+func (s *tracedUserService) DeleteUser(ctx context.Context, id string, version uint) (err error) {
+	ctx, span := startSpan(ctx, "UserService.DeleteUser", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+	return s.next.DeleteUser(ctx, id, version)
+}
+
+func (s *tracedUserService) FindUserByID(ctx context.Context, id string) (user *models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.FindUserByID", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+	return s.next.FindUserByID(ctx, id)
+}
+
+func (s *tracedUserService) DisableUser(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "UserService.DisableUser", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+	return s.next.DisableUser(ctx, id)
+}
+
+func (s *tracedUserService) ResetPassword(ctx context.Context, id string, password string) (err error) {
+	ctx, span := startSpan(ctx, "UserService.ResetPassword", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+	return s.next.ResetPassword(ctx, id, password)
+}
+
+// tracedRoleService membungkus RoleService dan membuat span untuk setiap method
+type tracedRoleService struct {
+	next RoleService
+}
+
+// NewTracedRoleService membungkus next sehingga setiap pemanggilan tercatat sebagai span
+func NewTracedRoleService(next RoleService) RoleService {
+	return &tracedRoleService{next}
+}
+
+func (s *tracedRoleService) GrantRole(ctx context.Context, userID string, roleName string) (err error) {
+	ctx, span := startSpan(ctx, "RoleService.GrantRole", attribute.String("user.id", userID), attribute.String("role", roleName))
+	defer func() { endSpan(span, err) }()
+	return s.next.GrantRole(ctx, userID, roleName)
+}
+
+func (s *tracedRoleService) RevokeRole(ctx context.Context, userID string, roleName string) (err error) {
+	ctx, span := startSpan(ctx, "RoleService.RevokeRole", attribute.String("user.id", userID), attribute.String("role", roleName))
+	defer func() { endSpan(span, err) }()
+	return s.next.RevokeRole(ctx, userID, roleName)
+}
@@ -1,56 +1,88 @@
 package service
 
 import (
-	"errors"
+	"context"
 	"project/internal/models"
 	"project/internal/repository"
+	"project/pkg/logger"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// log adalah logger komponen "service"
+var log = logger.New("service")
+
 type UserService interface {
-	GetAllUsers(page, limit int, sort string, filter map[string]interface{}) ([]models.User, int64, error)
-	GetUserByID(id string) (models.User, error)
-	GetUserByUsername(username string) (models.User, error)
-	CreateUser(user *models.User) error
-	UpdateUser(id string, user *models.User) error
-	DeleteUser(id string) error
-	FindUserByID(id string) (*models.User, error)
+	GetAllUsers(ctx context.Context, page, limit int, sort string, filter map[string]interface{}) ([]models.User, int64, error)
+	GetUserByID(ctx context.Context, id string) (models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	CreateUserWithRoles(ctx context.Context, user *models.User, roleNames []string) error
+	UpdateUser(ctx context.Context, id string, user *models.User) error
+	PatchUser(ctx context.Context, id string, version uint, fields map[string]interface{}) (models.User, error)
+	DeleteUser(ctx context.Context, id string, version uint) error
+	FindUserByID(ctx context.Context, id string) (*models.User, error)
+	DisableUser(ctx context.Context, id string) error
+	ResetPassword(ctx context.Context, id string, password string) error
 }
 
 type userService struct {
-	repo repository.UserRepository
+	repo      repository.UserRepository
+	txManager repository.TransactionManager
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo}
+func NewUserService(repo repository.UserRepository, txManager repository.TransactionManager) UserService {
+	return &userService{repo, txManager}
 }
 
-func (s *userService) GetAllUsers(page, limit int, sort string, filter map[string]interface{}) ([]models.User, int64, error) {
+func (s *userService) GetAllUsers(ctx context.Context, page, limit int, sort string, filter map[string]interface{}) ([]models.User, int64, error) {
 	// Memanggil repository untuk mendapatkan semua user dengan filter, pagination, dan sorting
-	users, total, err := s.repo.GetAllUsers(page, limit, sort, filter)
+	users, total, err := s.repo.GetAllUsers(ctx, page, limit, sort, filter)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, translateError(err)
 	}
 	return users, total, nil
 }
 
-func (s *userService) GetUserByID(id string) (models.User, error) {
+func (s *userService) GetUserByID(ctx context.Context, id string) (models.User, error) {
 	// Memanggil repository untuk mendapatkan user berdasarkan ID
-	user, err := s.repo.GetUserByID(id)
+	user, err := s.repo.GetUserByID(ctx, id)
 	if err != nil {
-		return models.User{}, err
+		return models.User{}, translateError(err)
 	}
 	return user, nil
 }
 
-func (s *userService) GetUserByUsername(username string) (models.User, error) {
-	return s.repo.GetUserByUsername(username)
+func (s *userService) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	return user, translateError(err)
 }
 
-func (s *userService) CreateUser(user *models.User) error {
-	return s.repo.CreateUser(user)
+func (s *userService) CreateUser(ctx context.Context, user *models.User) error {
+	if err := s.repo.CreateUser(ctx, user); err != nil {
+		return translateError(err)
+	}
+	log.InfoContext(ctx, "user created", "user_id", user.ID, "role", user.Role)
+	return nil
+}
+
+// CreateUserWithRoles membuat user sekaligus mengaitkan role-nya dalam satu transaksi,
+// sehingga user tidak tersimpan jika pengaitan role gagal
+func (s *userService) CreateUserWithRoles(ctx context.Context, user *models.User, roleNames []string) error {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return s.repo.AssignRoles(ctx, user, roleNames)
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	log.InfoContext(ctx, "user created", "user_id", user.ID, "role", user.Role, "roles", roleNames)
+	return nil
 }
 
 // HashPassword melakukan hashing terhadap password user
@@ -60,35 +92,86 @@ func HashPassword(password string) (string, error) {
 }
 
 // FindUserByID retrieves a user by their ID
-func (s *userService) FindUserByID(id string) (*models.User, error) {
-	return s.repo.FindByID(id)
+func (s *userService) FindUserByID(ctx context.Context, id string) (*models.User, error) {
+	user, err := s.repo.FindByID(ctx, id)
+	return user, translateError(err)
 }
 
-func (s *userService) UpdateUser(id string, user *models.User) error {
+func (s *userService) UpdateUser(ctx context.Context, id string, user *models.User) error {
 	// Use the repository to update the user in the database
-	if err := s.repo.UpdateUser(id, user); err != nil {
-		return err
+	if err := s.repo.UpdateUser(ctx, id, user); err != nil {
+		return translateError(err)
 	}
+	log.InfoContext(ctx, "user updated", "user_id", id, "version", user.Version)
 	return nil
 }
 
-// Definisikan ErrUserNotFound
-var ErrUserNotFound = errors.New("user not found")
+// PatchUser memperbarui sebagian kolom user lalu mengembalikan data terbaru.
+// fields berisi nama kolom database dan nilai barunya.
+// version adalah versi yang diharapkan (dari If-Match); 0 berarti tanpa prasyarat.
+func (s *userService) PatchUser(ctx context.Context, id string, version uint, fields map[string]interface{}) (models.User, error) {
+	// Memastikan user ada sebelum diperbarui
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return models.User{}, translateError(err)
+	}
+	if version != 0 && user.Version != version {
+		return models.User{}, ErrVersionConflict
+	}
+
+	if len(fields) > 0 {
+		if err := s.repo.UpdateUserFields(ctx, id, user.Version, fields); err != nil {
+			return models.User{}, translateError(err)
+		}
+		// Hanya nama kolom yang dicatat, nilainya bisa berisi hash password
+		columns := make([]string, 0, len(fields))
+		for column := range fields {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		log.InfoContext(ctx, "user patched", "user_id", id, "fields", columns)
+	}
 
-func (s *userService) DeleteUser(id string) error {
+	user, err = s.repo.GetUserByID(ctx, id)
+	return user, translateError(err)
+}
+
+// DeleteUser menghapus user; version 0 berarti tanpa prasyarat versi
+func (s *userService) DeleteUser(ctx context.Context, id string, version uint) error {
 	// Memastikan user ada sebelum menghapus
-	user, err := s.repo.GetUserByID(id)
+	user, err := s.repo.GetUserByID(ctx, id)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 	if user.ID == uuid.Nil {
 		// return errors.New("user not found")
 		return ErrUserNotFound
 	}
+	if version != 0 && user.Version != version {
+		return ErrVersionConflict
+	}
 
 	// Memanggil repository untuk menghapus user
-	if err := s.repo.DeleteUser(id); err != nil {
-		return err
+	if err := s.repo.DeleteUser(ctx, id, user.Version); err != nil {
+		return translateError(err)
 	}
+	log.InfoContext(ctx, "user deleted", "user_id", id)
 	return nil
 }
+
+// DisableUser menonaktifkan user sehingga tidak bisa login lagi
+func (s *userService) DisableUser(ctx context.Context, id string) error {
+	_, err := s.PatchUser(ctx, id, 0, map[string]interface{}{"disabled_at": time.Now()})
+	return err
+}
+
+// ResetPassword mengganti password user dengan password baru
+func (s *userService) ResetPassword(ctx context.Context, id string, password string) error {
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.PatchUser(ctx, id, 0, map[string]interface{}{"password": hashedPassword})
+	return err
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/repository"
+	"project/internal/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func newTestUserService(t *testing.T) (UserService, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	return NewUserService(repository.NewUserRepository(db), repository.NewTransactionManager(db)), db
+}
+
+func createServiceTestUser(t *testing.T, svc UserService, username string) models.User {
+	t.Helper()
+	user := models.User{Username: username, Password: "hash-" + username, Role: "viewer"}
+	if err := svc.CreateUser(context.Background(), &user); err != nil {
+		t.Fatalf("CreateUser(%s): %v", username, err)
+	}
+	return user
+}
+
+func TestUserServiceErrors(t *testing.T) {
+	svc, _ := newTestUserService(t)
+	ctx := context.Background()
+	user := createServiceTestUser(t, svc, "errors@mail.com")
+
+	if _, err := svc.GetUserByID(ctx, uuid.NewString()); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("GetUserByID(missing) error = %v, want ErrUserNotFound", err)
+	}
+
+	duplicate := models.User{Username: user.Username, Password: "other-hash", Role: "viewer"}
+	err := svc.CreateUser(ctx, &duplicate)
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || serviceErr.Kind != KindConflict {
+		t.Errorf("CreateUser(duplicate) error = %v, want Conflict", err)
+	}
+
+	if err := svc.DeleteUser(ctx, uuid.NewString(), 0); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("DeleteUser(missing) error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestUserServiceVersioning(t *testing.T) {
+	svc, _ := newTestUserService(t)
+	ctx := context.Background()
+	user := createServiceTestUser(t, svc, "versions@mail.com")
+	id := user.ID.String()
+
+	patched, err := svc.PatchUser(ctx, id, 1, map[string]interface{}{"role": "admin"})
+	if err != nil {
+		t.Fatalf("PatchUser: %v", err)
+	}
+	if patched.Role != "admin" || patched.Version != 2 {
+		t.Errorf("PatchUser = role %s version %d, want admin version 2", patched.Role, patched.Version)
+	}
+
+	if _, err := svc.PatchUser(ctx, id, 1, map[string]interface{}{"role": "viewer"}); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("PatchUser(stale) error = %v, want ErrVersionConflict", err)
+	}
+	if err := svc.UpdateUser(ctx, id, &user); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("UpdateUser(stale) error = %v, want ErrVersionConflict", err)
+	}
+	if err := svc.DeleteUser(ctx, id, 1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("DeleteUser(stale) error = %v, want ErrVersionConflict", err)
+	}
+
+	if err := svc.DisableUser(ctx, id); err != nil {
+		t.Fatalf("DisableUser: %v", err)
+	}
+	disabled, err := svc.GetUserByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if disabled.DisabledAt == nil {
+		t.Error("DisabledAt is nil after DisableUser")
+	}
+	if err := svc.DeleteUser(ctx, id, disabled.Version); err != nil {
+		t.Errorf("DeleteUser: %v", err)
+	}
+}
+
+func countUsers(t *testing.T, db *gorm.DB, username string) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&models.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		t.Fatalf("count users: %v", err)
+	}
+	return count
+}
+
+func TestCreateUserWithRolesRollsBackOnUnknownRole(t *testing.T) {
+	svc, db := newTestUserService(t)
+	testutil.CreateRoles(t, db, "admin")
+
+	user := models.User{Username: "rollback@mail.com", Password: "hash-rollback", Role: "admin"}
+	err := svc.CreateUserWithRoles(context.Background(), &user, []string{"admin", "missing"})
+	if !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("CreateUserWithRoles error = %v, want ErrRoleNotFound", err)
+	}
+	if count := countUsers(t, db, user.Username); count != 0 {
+		t.Errorf("users with username %s = %d after rollback, want 0", user.Username, count)
+	}
+}
+
+func TestCreateUserWithRolesDuplicateRoleNames(t *testing.T) {
+	svc, db := newTestUserService(t)
+	testutil.CreateRoles(t, db, "admin", "viewer")
+
+	user := models.User{Username: "dup@mail.com", Password: "hash-dup", Role: "admin"}
+	if err := svc.CreateUserWithRoles(context.Background(), &user, []string{"admin", "viewer", "admin"}); err != nil {
+		t.Fatalf("CreateUserWithRoles: %v", err)
+	}
+
+	var saved models.User
+	if err := db.Preload("Roles").First(&saved, "id = ?", user.ID).Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if len(saved.Roles) != 2 {
+		t.Errorf("user has %d roles, want 2", len(saved.Roles))
+	}
+}
@@ -0,0 +1,42 @@
+// Package testutil berisi helper untuk test yang membutuhkan database
+package testutil
+
+import (
+	"project/internal/models"
+	"project/pkg/config"
+	"project/pkg/database"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// NewDB membuka database SQLite in-memory lewat database.ConnectDB dan menerapkan
+// semua migrasi yang di-embed. Database ditutup otomatis saat test selesai.
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Database.Driver = database.DriverSQLite
+	cfg.Database.DBName = ":memory:"
+
+	db, err := database.ConnectDB(cfg)
+	if err != nil {
+		t.Fatalf("connect to sqlite: %v", err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+
+	if err := database.MigrateDatabase(db, nil); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	return db
+}
+
+// CreateRoles menyimpan role dengan nama names
+func CreateRoles(t testing.TB, db *gorm.DB, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := db.Create(&models.Role{Name: name}).Error; err != nil {
+			t.Fatalf("create role %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"project/internal/repository"
+	"project/pkg/i18n"
+	"project/pkg/logger"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Tag aturan validasi custom
+const (
+	// TagStrongPassword - Minimal 8 karakter dengan huruf besar, huruf kecil dan angka
+	TagStrongPassword = "strong_password"
+	// TagRoleExists - Role dengan nama tersebut sudah ada di database
+	TagRoleExists = "role_exists"
+	// TagUniqueUsername - Username belum dipakai user lain
+	TagUniqueUsername = "unique_username"
+	// TagSupportedLocale - Locale yang punya katalog pesan (lihat i18n.Locales)
+	TagSupportedLocale = "supported_locale"
+)
+
+// minPasswordLength - Panjang minimal password untuk strong_password
+const minPasswordLength = 8
+
+// log adalah logger komponen "validator"
+var log = logger.New("validator")
+
+// Repository untuk aturan yang butuh database; nil berarti aturan tersebut dilewati
+var (
+	userRepository repository.UserRepository
+	roleRepository repository.RoleRepository
+)
+
+// UseRepositories mengaktifkan aturan role_exists dan unique_username.
+// Jika query gagal, nilai dianggap valid; constraint database tetap menjaga
+// konsistensi dan error-nya dikembalikan oleh service.
+func UseRepositories(users repository.UserRepository, roles repository.RoleRepository) {
+	userRepository = users
+	roleRepository = roles
+}
+
+func registerRules(v *validator.Validate) {
+	_ = v.RegisterValidation(TagStrongPassword, strongPassword)
+	_ = v.RegisterValidationCtx(TagRoleExists, roleExists)
+	_ = v.RegisterValidationCtx(TagUniqueUsername, uniqueUsername)
+	_ = v.RegisterValidation(TagSupportedLocale, func(fl validator.FieldLevel) bool {
+		return i18n.Supported(fl.Field().String())
+	})
+}
+
+// strongPassword memastikan password cukup panjang dan memuat huruf besar, huruf kecil dan angka
+func strongPassword(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+	if len([]rune(password)) < minPasswordLength {
+		return false
+	}
+
+	var upper, lower, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+func roleExists(ctx context.Context, fl validator.FieldLevel) bool {
+	if roleRepository == nil {
+		return true
+	}
+	_, err := roleRepository.GetRoleByName(ctx, fl.Field().String())
+	if errors.Is(err, repository.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		log.WarnContext(ctx, "role_exists check failed, value accepted", "error", err)
+	}
+	return true
+}
+
+func uniqueUsername(ctx context.Context, fl validator.FieldLevel) bool {
+	if userRepository == nil {
+		return true
+	}
+	_, err := userRepository.GetUserByUsername(ctx, fl.Field().String())
+	if errors.Is(err, repository.ErrNotFound) {
+		return true
+	}
+	if err != nil {
+		log.WarnContext(ctx, "unique_username check failed, value accepted", "error", err)
+		return true
+	}
+	return false
+}
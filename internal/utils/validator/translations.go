@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+// universalTranslator menyimpan translator untuk setiap bahasa yang didukung; fallback English
+var universalTranslator *ut.UniversalTranslator
+
+// customMessages - Pesan untuk aturan custom per bahasa; {0} adalah nama field
+var customMessages = map[string]map[string]string{
+	"en": {
+		TagStrongPassword:  "{0} must be at least 8 characters and contain an uppercase letter, a lowercase letter and a digit",
+		TagRoleExists:      "{0} must be an existing role",
+		TagUniqueUsername:  "{0} is already taken",
+		TagSupportedLocale: "{0} must be a supported language",
+	},
+	"id": {
+		TagStrongPassword:  "{0} minimal 8 karakter dan harus berisi huruf besar, huruf kecil dan angka",
+		TagRoleExists:      "{0} harus berupa role yang sudah ada",
+		TagUniqueUsername:  "{0} sudah dipakai",
+		TagSupportedLocale: "{0} harus berupa bahasa yang didukung",
+	},
+}
+
+func registerTranslations(v *validator.Validate) {
+	english := en.New()
+	universalTranslator = ut.New(english, english, id.New())
+
+	enTrans, _ := universalTranslator.GetTranslator("en")
+	idTrans, _ := universalTranslator.GetTranslator("id")
+	_ = enTranslations.RegisterDefaultTranslations(v, enTrans)
+	_ = idTranslations.RegisterDefaultTranslations(v, idTrans)
+
+	for locale, messages := range customMessages {
+		trans, _ := universalTranslator.GetTranslator(locale)
+		for tag, message := range messages {
+			_ = v.RegisterTranslation(tag, trans, addTranslation(tag, message), translateField)
+		}
+	}
+}
+
+func addTranslation(tag, message string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, message, true)
+	}
+}
+
+func translateField(trans ut.Translator, fe validator.FieldError) string {
+	message, err := trans.T(fe.Tag(), fe.Field())
+	if err != nil {
+		return fe.Error()
+	}
+	return message
+}
+
+// translatorFor mengembalikan translator untuk locale, atau English jika tidak didukung
+func translatorFor(locale string) ut.Translator {
+	trans, _ := universalTranslator.FindTranslator(locale)
+	return trans
+}
@@ -1,17 +1,93 @@
 package validator
 
 import (
+	"context"
+	"errors"
+	"project/pkg/i18n"
+	"reflect"
+	"sort"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
 )
 
 // Validator instance
 var validate *validator.Validate
 
+// ErrInvalidBody - Body request tidak bisa di-parse ke struct tujuan
+var ErrInvalidBody = errors.New("invalid request body")
+
+// FieldErrors berisi pesan error per field (nama JSON) yang sudah diterjemahkan
+type FieldErrors map[string]string
+
+func (e FieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, e[field])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// InitValidator menyiapkan validator: nama field diambil dari tag json, aturan
+// custom didaftarkan, dan pesan error diterjemahkan (en, id).
+// Aturan yang butuh database baru aktif setelah UseRepositories dipanggil.
 func InitValidator() {
 	validate = validator.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
+	registerRules(validate)
+	registerTranslations(validate)
 }
 
 // ValidateStruct validates a struct based on the tags
 func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
+
+// Validate memvalidasi s dan mengembalikan FieldErrors dengan pesan dalam locale
+// dari ctx (lihat i18n.WithLocale; default English)
+func Validate(ctx context.Context, s interface{}) error {
+	err := validate.StructCtx(ctx, s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	trans := translatorFor(i18n.FromContext(ctx))
+	fields := make(FieldErrors, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields[fieldErr.Field()] = fieldErr.Translate(trans)
+	}
+	return fields
+}
+
+// BindAndValidate mem-parsing body request ke T lalu memvalidasinya dengan locale
+// request. Mengembalikan ErrInvalidBody atau FieldErrors jika gagal.
+func BindAndValidate[T any](c *fiber.Ctx) (T, error) {
+	var req T
+	if err := c.BodyParser(&req); err != nil {
+		return req, ErrInvalidBody
+	}
+	return req, Validate(c.UserContext(), &req)
+}
+
+// jsonFieldName memakai nama dari tag json sebagai nama field di pesan error
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
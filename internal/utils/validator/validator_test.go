@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"project/pkg/i18n"
+	"reflect"
+	"testing"
+)
+
+func TestStrongPassword(t *testing.T) {
+	InitValidator()
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"Secret123", true},
+		{"Rahasia1", true},
+		{"Ünïcödé1", true},
+		{"Secret1", false},
+		{"secret123", false},
+		{"SECRET123", false},
+		{"SecretABC", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		err := validate.Var(tt.password, TagStrongPassword)
+		if got := err == nil; got != tt.want {
+			t.Errorf("strong_password(%q) = %t, want %t", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestJSONFieldName(t *testing.T) {
+	type sample struct {
+		Username string `json:"username,omitempty"`
+		Password string `json:"password"`
+		Internal string `json:"-"`
+		Role     string
+	}
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"Username", "username"},
+		{"Password", "password"},
+		{"Internal", ""},
+		{"Role", "Role"},
+	}
+	typ := reflect.TypeOf(sample{})
+	for _, tt := range tests {
+		field, _ := typ.FieldByName(tt.field)
+		if got := jsonFieldName(field); got != tt.want {
+			t.Errorf("jsonFieldName(%s) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTranslatesMessages(t *testing.T) {
+	InitValidator()
+
+	type request struct {
+		Username string `json:"username" validate:"required,email"`
+		Password string `json:"password" validate:"strong_password"`
+	}
+	req := request{Password: "weak"}
+
+	tests := []struct {
+		locale string
+		want   FieldErrors
+	}{
+		{"en", FieldErrors{
+			"username": "username is a required field",
+			"password": "password must be at least 8 characters and contain an uppercase letter, a lowercase letter and a digit",
+		}},
+		{"id", FieldErrors{
+			"username": "username wajib diisi",
+			"password": "password minimal 8 karakter dan harus berisi huruf besar, huruf kecil dan angka",
+		}},
+		// Locale yang tidak didukung memakai English
+		{"fr", FieldErrors{
+			"username": "username is a required field",
+			"password": "password must be at least 8 characters and contain an uppercase letter, a lowercase letter and a digit",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			err := Validate(i18n.WithLocale(context.Background(), tt.locale), &req)
+			var fields FieldErrors
+			if !errors.As(err, &fields) {
+				t.Fatalf("Validate error = %v, want FieldErrors", err)
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("FieldErrors = %v, want %v", fields, tt.want)
+			}
+		})
+	}
+}
@@ -1,63 +1,300 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"os"
+	"path/filepath"
+	"project/pkg/logger"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// EnvPrefix adalah prefix environment variable yang menimpa config: key ditulis
+// huruf besar dengan "." diganti "_", contoh database.host -> USER_API_DATABASE_HOST.
+// Tambahkan akhiran _FILE untuk membaca nilainya dari file (secret Docker/Kubernetes),
+// contoh USER_API_APP_JWT_SECRET_FILE=/run/secrets/jwt_secret.
+const EnvPrefix = "USER_API"
+
+// EnvConfigFile adalah environment variable untuk path file config utama
+const EnvConfigFile = EnvPrefix + "_CONFIG"
+
+// DefaultConfigFile adalah path file config utama jika EnvConfigFile kosong
+const DefaultConfigFile = "config/config.yaml"
+
+// log adalah logger komponen "config"
+var log = logger.New("config")
+
 type Config struct {
+	// files adalah file config yang dibaca (file utama lalu overlay environment)
+	files []string
+	// settings adalah semua nilai config (key datar) untuk membandingkan saat reload
+	settings map[string]interface{}
+
 	App struct {
+		// Env adalah nama environment (development, test, staging, production)
+		Env       string
 		Port      string
 		JWTSecret string `mapstructure:"jwt_secret"`
+		// RequireIfMatch mewajibkan header If-Match pada PUT/PATCH/DELETE user (428 jika kosong)
+		RequireIfMatch bool `mapstructure:"require_if_match"`
+		// HealthCheckTimeout membatasi lama setiap check di /readyz
+		HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"`
+		// ShutdownDelay adalah jeda antara /readyz melaporkan tidak siap dan server
+		// berhenti menerima koneksi, agar load balancer sempat melepas instance ini
+		ShutdownDelay time.Duration `mapstructure:"shutdown_delay"`
+		// ShutdownTimeout adalah batas waktu menunggu request yang sedang berjalan selesai
+		ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 	}
 	Database struct {
+		// Driver: postgres (default), mysql atau sqlite
+		Driver   string
 		Host     string
 		Port     int
 		User     string
 		Password string
-		DBName   string `mapstructure:"dbname"`
+		// DBName adalah nama database; untuk sqlite berisi path file (":memory:" untuk in-memory)
+		DBName string `mapstructure:"dbname"`
+		// SSLMode: disable, require, verify-ca, verify-full (postgres juga menerima allow/prefer)
+		SSLMode     string `mapstructure:"sslmode"`
+		SSLRootCert string `mapstructure:"sslrootcert"`
+		SSLCert     string `mapstructure:"sslcert"`
+		SSLKey      string `mapstructure:"sslkey"`
+		// QueryTimeout membatasi lama query per request (contoh: "5s"); 0 berarti tanpa batas
+		QueryTimeout time.Duration `mapstructure:"query_timeout"`
+		// MigrateOnStart menjalankan migrasi yang tertunda saat aplikasi start
+		MigrateOnStart bool `mapstructure:"migrate_on_start"`
+		// Pengaturan connection pool; 0 berarti memakai default database/sql
+		MaxOpenConns    int           `mapstructure:"max_open_conns"`
+		MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+		ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+		ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+		// ConnectRetries adalah jumlah percobaan koneksi saat start, dengan jeda
+		// ConnectBackoff yang berlipat ganda hingga ConnectMaxBackoff
+		ConnectRetries    int           `mapstructure:"connect_retries"`
+		ConnectBackoff    time.Duration `mapstructure:"connect_backoff"`
+		ConnectMaxBackoff time.Duration `mapstructure:"connect_max_backoff"`
+		// PingInterval adalah jeda ping berkala ke database; 0 menonaktifkan ping
+		PingInterval time.Duration `mapstructure:"ping_interval"`
+		// Replicas berisi DSN read replica (format sesuai driver); query baca diarahkan
+		// ke replica, query tulis dan transaksi tetap ke primary
+		Replicas []string `mapstructure:"replicas"`
 	}
+	// RateLimit mengatur pembatasan request (token bucket) per group route dan per principal
+	RateLimit struct {
+		Enabled bool
+		// Groups berisi limit per group route: login, users, audit
+		Groups map[string]RateLimitRule
+		// Principals menimpa limit untuk principal tertentu di semua group
+		Principals []RateLimitPrincipal
+		// CleanupInterval adalah jeda pembersihan bucket yang sudah penuh dari memori
+		CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+	} `mapstructure:"rate_limit"`
 	Logging struct {
 		ELKHost string `mapstructure:"elk_host"`
 		APMHost string `mapstructure:"apm_host"`
+		// Level default: debug, info, warn atau error
+		Level string `mapstructure:"level"`
+		// Format: json atau text
+		Format string `mapstructure:"format"`
+		// Levels menimpa level per komponen (handler, service, repository, seeder, database, gorm, http, app)
+		Levels map[string]string `mapstructure:"levels"`
+		// SlowQueryThreshold: query yang lebih lama dicatat sebagai warn; 0 menonaktifkan
+		SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+		// ELKEnabled mengirim log ke Elasticsearch di ELKHost (bulk, asinkron)
+		ELKEnabled bool `mapstructure:"elk_enabled"`
+		// ELKIndex boleh berisi layout tanggal, contoh "app-logs-{2006.01.02}"
+		ELKIndex         string        `mapstructure:"elk_index"`
+		ELKBatchSize     int           `mapstructure:"elk_batch_size"`
+		ELKFlushInterval time.Duration `mapstructure:"elk_flush_interval"`
+		// ELKBufferSize membatasi jumlah log yang menunggu dikirim; jika penuh log dibuang (dihitung)
+		ELKBufferSize int `mapstructure:"elk_buffer_size"`
+		ELKMaxRetries int `mapstructure:"elk_max_retries"`
+		// TraceExporter: none, otlp (OTLP/HTTP ke APMHost) atau stdout (untuk development)
+		TraceExporter string `mapstructure:"trace_exporter"`
+		// TraceSampleRatio adalah rasio trace baru yang direkam (0..1)
+		TraceSampleRatio float64 `mapstructure:"trace_sample_ratio"`
+		TraceServiceName string  `mapstructure:"trace_service_name"`
+		// APMInsecure mengirim trace ke APMHost tanpa TLS
+		APMInsecure bool `mapstructure:"apm_insecure"`
 	}
 }
 
+// RateLimitRule - Requests request per Period, dengan lonjakan maksimal Burst (default Requests)
+type RateLimitRule struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// RateLimitPrincipal - Limit khusus untuk satu principal: "user:<id>" atau "ip:<alamat>"
+type RateLimitPrincipal struct {
+	Principal     string
+	RateLimitRule `mapstructure:",squash"`
+}
+
+// LoadConfig membaca config dari (berurutan, yang terakhir menang): nilai default,
+// file config utama, overlay config.<app.env>.yaml di folder yang sama (jika ada),
+// environment variable dengan EnvPrefix, dan file secret dari <VAR>_FILE.
+// Config yang tidak valid (lihat Validate) dikembalikan sebagai error.
 func LoadConfig() (*Config, error) {
-	var config Config
+	v := viper.New()
+	setDefaults(v)
 
-	// Set default values (you can adjust these according to your preferences)
-	viper.SetDefault("App.Port", "8080")
-	viper.SetDefault("App.JWTSecret", "whatIsTheSecretAbout78")
-	viper.SetDefault("Database.Host", "localhost")
-	viper.SetDefault("Database.Port", 5432)
-	viper.SetDefault("Database.User", "root")
-	viper.SetDefault("Database.Password", "password")
-	viper.SetDefault("Database.DBName", "boiler_db")
-	viper.SetDefault("Logging.ELKHost", "localhost:9200")
-	viper.SetDefault("Logging.APMHost", "localhost:8200")
-
-	// Set config file path and name
-	viper.AddConfigPath("config")
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-
-	// Allow environment variables to override config file values
-	viper.AutomaticEnv()
-
-	// Read in the configuration file
-	if err := viper.ReadInConfig(); err != nil {
-		// Handle file not found or read errors
-		log.Printf("Error reading config file, %s", err)
-		// If you want, you can either exit or fallback to defaults.
-	}
-
-	// Unmarshal the configuration into the struct
-	if err := viper.Unmarshal(&config); err != nil {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	file := os.Getenv(EnvConfigFile)
+	if file == "" {
+		file = DefaultConfigFile
+	}
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read config file %s: %w", file, err)
+	}
+	files := []string{file}
+
+	// Overlay per environment, contoh config/config.production.yaml
+	env := v.GetString("app.env")
+	overlay := filepath.Join(filepath.Dir(file), "config."+env+filepath.Ext(file))
+	if _, err := os.Stat(overlay); err == nil {
+		v.SetConfigFile(overlay)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("read config overlay %s: %w", overlay, err)
+		}
+		files = append(files, overlay)
+	}
+
+	if err := readSecretFiles(v); err != nil {
 		return nil, err
 	}
 
-	// Return the loaded config
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, err
+	}
+	config.files = files
+	config.settings = make(map[string]interface{})
+	for _, key := range v.AllKeys() {
+		config.settings[key] = v.Get(key)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
 	return &config, nil
 }
+
+// IsDevelopment melaporkan apakah aplikasi berjalan di development atau test,
+// satu-satunya environment yang boleh memakai secret lemah
+func (c *Config) IsDevelopment() bool {
+	return c.App.Env == "development" || c.App.Env == "test"
+}
+
+// Files mengembalikan file config yang dibaca, file utama lebih dulu
+func (c *Config) Files() []string {
+	return c.files
+}
+
+// readSecretFiles mengisi setiap key yang punya environment variable <VAR>_FILE
+// dengan isi file tersebut (tanpa baris baru di akhir)
+func readSecretFiles(v *viper.Viper) error {
+	for _, key := range v.AllKeys() {
+		envFile := envName(key) + "_FILE"
+		path := os.Getenv(envFile)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", envFile, err)
+		}
+		v.Set(key, strings.TrimRight(string(data), "\r\n"))
+	}
+	return nil
+}
+
+// envName mengembalikan environment variable untuk key, contoh app.jwt_secret -> USER_API_APP_JWT_SECRET
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// changedKeys mengembalikan key yang nilainya berbeda antara dua config, terurut
+func changedKeys(old, next *Config) []string {
+	var keys []string
+	for key, value := range next.settings {
+		if !reflect.DeepEqual(old.settings[key], value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range old.settings {
+		if _, ok := next.settings[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func setDefaults(v *viper.Viper) {
+	// Tidak ada default untuk app.jwt_secret dan database.password: keduanya
+	// harus diisi lewat file config, environment variable atau file secret.
+	// app.env default production agar validasi ketat tidak terlewati hanya karena
+	// env lupa diisi; development/test harus dipilih secara eksplisit.
+	v.SetDefault("app.env", "production")
+	v.SetDefault("app.port", "8080")
+	v.SetDefault("app.jwt_secret", "")
+	v.SetDefault("app.require_if_match", false)
+	v.SetDefault("app.health_check_timeout", "2s")
+	v.SetDefault("app.shutdown_delay", "0s")
+	v.SetDefault("app.shutdown_timeout", "30s")
+	v.SetDefault("database.driver", "postgres")
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", 5432)
+	v.SetDefault("database.user", "root")
+	v.SetDefault("database.password", "")
+	v.SetDefault("database.dbname", "boiler_db")
+	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.sslrootcert", "")
+	v.SetDefault("database.sslcert", "")
+	v.SetDefault("database.sslkey", "")
+	v.SetDefault("database.query_timeout", "5s")
+	v.SetDefault("database.migrate_on_start", true)
+	v.SetDefault("database.max_open_conns", 25)
+	v.SetDefault("database.max_idle_conns", 10)
+	v.SetDefault("database.conn_max_lifetime", "30m")
+	v.SetDefault("database.conn_max_idle_time", "5m")
+	v.SetDefault("database.connect_retries", 10)
+	v.SetDefault("database.connect_backoff", "1s")
+	v.SetDefault("database.connect_max_backoff", "30s")
+	v.SetDefault("database.ping_interval", "30s")
+	v.SetDefault("database.replicas", []string{})
+	v.SetDefault("rate_limit.enabled", true)
+	v.SetDefault("rate_limit.groups", map[string]interface{}{
+		"login": map[string]interface{}{"requests": 10, "period": "1m", "burst": 5},
+		"users": map[string]interface{}{"requests": 300, "period": "1m"},
+		"audit": map[string]interface{}{"requests": 60, "period": "1m"},
+	})
+	v.SetDefault("rate_limit.principals", []interface{}{})
+	v.SetDefault("rate_limit.cleanup_interval", "1m")
+	v.SetDefault("logging.elk_host", "localhost:9200")
+	v.SetDefault("logging.apm_host", "localhost:8200")
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.format", "json")
+	v.SetDefault("logging.levels", map[string]string{})
+	v.SetDefault("logging.slow_query_threshold", "200ms")
+	v.SetDefault("logging.elk_enabled", false)
+	v.SetDefault("logging.elk_index", "app-logs-{2006.01.02}")
+	v.SetDefault("logging.elk_batch_size", 500)
+	v.SetDefault("logging.elk_flush_interval", "2s")
+	v.SetDefault("logging.elk_buffer_size", 10000)
+	v.SetDefault("logging.elk_max_retries", 3)
+	v.SetDefault("logging.trace_exporter", "none")
+	v.SetDefault("logging.trace_sample_ratio", 1.0)
+	v.SetDefault("logging.trace_service_name", "user-management-api")
+	v.SetDefault("logging.apm_insecure", true)
+}
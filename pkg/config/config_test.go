@@ -0,0 +1,259 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	strongSecret   = "a-production-secret-of-32-characters!"
+	strongPassword = "db-password-for-tests"
+)
+
+// baseConfig adalah config utama yang valid di production
+var baseConfig = `app:
+  env: production
+  port: "8080"
+  jwt_secret: "` + strongSecret + `"
+database:
+  host: db.internal
+  password: "` + strongPassword + `"
+  max_open_conns: 25
+rate_limit:
+  groups:
+    login:
+      requests: 10
+      period: 1m
+logging:
+  level: info
+`
+
+// loadFiles menulis files (nama -> isi) ke folder sementara, memakai config.yaml
+// sebagai file utama, mengisi env lalu memanggil LoadConfig
+func loadFiles(t *testing.T, files, env map[string]string) (*Config, error) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(EnvConfigFile, filepath.Join(dir, "config.yaml"))
+	for key, value := range env {
+		t.Setenv(key, strings.ReplaceAll(value, "$DIR", dir))
+	}
+	return LoadConfig()
+}
+
+// load memuat baseConfig dengan env
+func load(t *testing.T, env map[string]string) *Config {
+	t.Helper()
+	cfg, err := loadFiles(t, map[string]string{"config.yaml": baseConfig}, env)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(c *Config)
+		// want berisi key yang harus muncul di error; kosong berarti valid
+		want []string
+	}{
+		{"valid production", func(c *Config) {}, nil},
+		{"unknown env", func(c *Config) { c.App.Env = "prod" }, []string{"app.env"}},
+		{"invalid port", func(c *Config) { c.App.Port = "0" }, []string{"app.port"}},
+		{"missing jwt secret", func(c *Config) { c.App.JWTSecret = "" }, []string{"app.jwt_secret", "APP_JWT_SECRET_FILE"}},
+		{"weak jwt secret in production", func(c *Config) { c.App.JWTSecret = "changeme" }, []string{"app.jwt_secret"}},
+		{"short jwt secret in staging", func(c *Config) { c.App.Env, c.App.JWTSecret = "staging", "short-but-unique" }, []string{"app.jwt_secret"}},
+		{"weak secrets in development", func(c *Config) {
+			c.App.Env, c.App.JWTSecret, c.Database.Password = "development", "secret", ""
+		}, nil},
+		{"missing database password in production", func(c *Config) { c.Database.Password = "" }, []string{"database.password"}},
+		{"sqlite needs no host or password", func(c *Config) {
+			c.Database.Driver, c.Database.Host, c.Database.Password, c.Database.Port = "sqlite", "", "", 0
+		}, nil},
+		{"unknown driver", func(c *Config) { c.Database.Driver = "oracle" }, []string{"database.driver"}},
+		{"unknown sslmode", func(c *Config) { c.Database.SSLMode = "always" }, []string{"database.sslmode"}},
+		{"negative duration", func(c *Config) { c.Database.QueryTimeout = -time.Second }, []string{"database.query_timeout"}},
+		{"invalid rate limit group", func(c *Config) {
+			c.RateLimit.Groups["login"] = RateLimitRule{Requests: 10}
+		}, []string{"rate_limit.groups.login"}},
+		{"principal without name", func(c *Config) {
+			c.RateLimit.Principals = []RateLimitPrincipal{{RateLimitRule: RateLimitRule{Requests: 1, Period: time.Second}}}
+		}, []string{"rate_limit.principals"}},
+		{"unknown log level", func(c *Config) { c.Logging.Levels = map[string]string{"http": "loud"} }, []string{"logging.levels.http"}},
+		{"elk without host", func(c *Config) { c.Logging.ELKEnabled, c.Logging.ELKHost = true, "" }, []string{"logging.elk_host"}},
+		{"sample ratio out of range", func(c *Config) { c.Logging.TraceSampleRatio = 2 }, []string{"logging.trace_sample_ratio"}},
+		{"all problems are reported", func(c *Config) {
+			c.App.Port, c.Database.Driver, c.Logging.Format = "http", "oracle", "xml"
+		}, []string{"app.port", "database.driver", "logging.format"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := load(t, nil)
+			tt.edit(cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate = nil, want errors for %v", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate = %v, want it to mention %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigRejectsInvalidConfig(t *testing.T) {
+	_, err := loadFiles(t, map[string]string{"config.yaml": baseConfig}, map[string]string{
+		"USER_API_APP_JWT_SECRET": "changeme",
+	})
+	if err == nil || !strings.Contains(err.Error(), "app.jwt_secret") {
+		t.Fatalf("LoadConfig = %v, want an app.jwt_secret error", err)
+	}
+}
+
+func TestLoadConfigEnvMapping(t *testing.T) {
+	cfg := load(t, map[string]string{
+		"USER_API_APP_PORT":                "9090",
+		"USER_API_DATABASE_HOST":           "primary.internal",
+		"USER_API_DATABASE_MAX_OPEN_CONNS": "7",
+		"USER_API_DATABASE_QUERY_TIMEOUT":  "750ms",
+		"USER_API_RATE_LIMIT_ENABLED":      "false",
+		"USER_API_LOGGING_LEVEL":           "debug",
+	})
+
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"app.port", cfg.App.Port, "9090"},
+		{"database.host", cfg.Database.Host, "primary.internal"},
+		{"database.max_open_conns", cfg.Database.MaxOpenConns, 7},
+		{"database.query_timeout", cfg.Database.QueryTimeout, 750 * time.Millisecond},
+		{"rate_limit.enabled", cfg.RateLimit.Enabled, false},
+		{"logging.level", cfg.Logging.Level, "debug"},
+		// Key yang tidak di-override tetap dari file atau default
+		{"database.password", cfg.Database.Password, strongPassword},
+		{"database.max_idle_conns", cfg.Database.MaxIdleConns, 10},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %#v, want %#v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigSecretFiles(t *testing.T) {
+	secret := strings.Repeat("s", minSecretLength) + "-from-file"
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		env     map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "trailing newline is trimmed",
+			files: map[string]string{"jwt_secret": secret + "\n"},
+			env:   map[string]string{"USER_API_APP_JWT_SECRET_FILE": "$DIR/jwt_secret"},
+			want:  secret,
+		},
+		{
+			name:  "file wins over the env var",
+			files: map[string]string{"jwt_secret": secret + "\r\n"},
+			env: map[string]string{
+				"USER_API_APP_JWT_SECRET":      "ignored-because-the-file-wins-over-it",
+				"USER_API_APP_JWT_SECRET_FILE": "$DIR/jwt_secret",
+			},
+			want: secret,
+		},
+		{
+			name:    "missing file",
+			env:     map[string]string{"USER_API_APP_JWT_SECRET_FILE": "$DIR/missing"},
+			wantErr: "USER_API_APP_JWT_SECRET_FILE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"config.yaml": baseConfig}
+			for name, content := range tt.files {
+				files[name] = content
+			}
+			cfg, err := loadFiles(t, files, tt.env)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig = %v, want an error mentioning %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if cfg.App.JWTSecret != tt.want {
+				t.Errorf("app.jwt_secret = %q, want %q", cfg.App.JWTSecret, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigOverlay(t *testing.T) {
+	overlay := `app:
+  port: "9443"
+database:
+  max_open_conns: 50
+logging:
+  levels:
+    http: debug
+`
+	tests := []struct {
+		name          string
+		env           map[string]string
+		wantOverlay   bool
+		wantPort      string
+		wantOpenConns int
+	}{
+		{"overlay of app.env from the file", nil, true, "9443", 50},
+		{"env var overrides the overlay", map[string]string{"USER_API_APP_PORT": "7000"}, true, "7000", 50},
+		{"app.env from the env var picks another overlay", map[string]string{"USER_API_APP_ENV": "staging"}, false, "8080", 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadFiles(t, map[string]string{
+				"config.yaml":            baseConfig,
+				"config.production.yaml": overlay,
+			}, tt.env)
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if got := len(cfg.Files()) == 2; got != tt.wantOverlay {
+				t.Errorf("Files = %v, want overlay read %t", cfg.Files(), tt.wantOverlay)
+			}
+			if cfg.App.Port != tt.wantPort || cfg.Database.MaxOpenConns != tt.wantOpenConns {
+				t.Errorf("port %s, max_open_conns %d, want %s and %d", cfg.App.Port, cfg.Database.MaxOpenConns, tt.wantPort, tt.wantOpenConns)
+			}
+			// Key yang tidak ada di overlay tetap dari file utama
+			if cfg.Database.Host != "db.internal" || cfg.RateLimit.Groups["login"].Requests != 10 {
+				t.Errorf("host %s, login requests %d, want values from config.yaml", cfg.Database.Host, cfg.RateLimit.Groups["login"].Requests)
+			}
+			if tt.wantOverlay && cfg.Logging.Levels["http"] != "debug" {
+				t.Errorf("logging.levels = %v, want http: debug from the overlay", cfg.Logging.Levels)
+			}
+		})
+	}
+}
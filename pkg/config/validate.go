@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"project/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minSecretLength - Panjang minimal app.jwt_secret di luar development/test
+const minSecretLength = 32
+
+// Nilai yang diterima untuk key yang berupa pilihan
+var (
+	validEnvs           = []string{"development", "test", "staging", "production"}
+	validDrivers        = []string{"postgres", "mysql", "sqlite"}
+	validSSLModes       = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	validLogFormats     = []string{"json", "text"}
+	validTraceExporters = []string{"none", "otlp", "stdout"}
+)
+
+// weakSecrets berisi secret contoh atau default yang tidak boleh dipakai di luar development/test
+var weakSecrets = []string{
+	"whatIsTheSecretAbout78",
+	"secret",
+	"password",
+	"changeme",
+	"change-me",
+	"jwt_secret",
+}
+
+// Validate memeriksa semua nilai config dan mengembalikan gabungan seluruh
+// masalah yang ditemukan (errors.Join), bukan hanya yang pertama. Di luar
+// development/test secret default atau lemah ditolak.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	// App
+	if !oneOf(c.App.Env, validEnvs) {
+		fail("app.env", "must be one of %s, got %q", strings.Join(validEnvs, ", "), c.App.Env)
+	}
+	if port, err := strconv.Atoi(c.App.Port); err != nil || port < 1 || port > 65535 {
+		fail("app.port", "must be a port number between 1 and 65535, got %q", c.App.Port)
+	}
+	switch {
+	case c.App.JWTSecret == "":
+		fail("app.jwt_secret", "is required (set %s or %s_FILE)", envName("app.jwt_secret"), envName("app.jwt_secret"))
+	case !c.IsDevelopment() && isWeakSecret(c.App.JWTSecret):
+		fail("app.jwt_secret", "must not be a default or example secret in %s", c.App.Env)
+	case !c.IsDevelopment() && len(c.App.JWTSecret) < minSecretLength:
+		fail("app.jwt_secret", "must be at least %d characters in %s", minSecretLength, c.App.Env)
+	}
+	nonNegative(fail, "app.health_check_timeout", c.App.HealthCheckTimeout)
+	nonNegative(fail, "app.shutdown_delay", c.App.ShutdownDelay)
+	nonNegative(fail, "app.shutdown_timeout", c.App.ShutdownTimeout)
+
+	// Database
+	driver := strings.ToLower(c.Database.Driver)
+	if !oneOf(driver, validDrivers) {
+		fail("database.driver", "must be one of %s, got %q", strings.Join(validDrivers, ", "), c.Database.Driver)
+	}
+	if c.Database.DBName == "" {
+		fail("database.dbname", "is required")
+	}
+	if driver != "sqlite" {
+		if c.Database.Host == "" {
+			fail("database.host", "is required")
+		}
+		if c.Database.Port < 1 || c.Database.Port > 65535 {
+			fail("database.port", "must be between 1 and 65535, got %d", c.Database.Port)
+		}
+		if c.Database.SSLMode != "" && !oneOf(c.Database.SSLMode, validSSLModes) {
+			fail("database.sslmode", "must be one of %s, got %q", strings.Join(validSSLModes, ", "), c.Database.SSLMode)
+		}
+		if !c.IsDevelopment() && (c.Database.Password == "" || isWeakSecret(c.Database.Password)) {
+			fail("database.password", "must not be empty or a default password in %s (set %s or %s_FILE)", c.App.Env, envName("database.password"), envName("database.password"))
+		}
+	}
+	nonNegative(fail, "database.query_timeout", c.Database.QueryTimeout)
+	nonNegative(fail, "database.conn_max_lifetime", c.Database.ConnMaxLifetime)
+	nonNegative(fail, "database.conn_max_idle_time", c.Database.ConnMaxIdleTime)
+	nonNegative(fail, "database.connect_backoff", c.Database.ConnectBackoff)
+	nonNegative(fail, "database.connect_max_backoff", c.Database.ConnectMaxBackoff)
+	nonNegative(fail, "database.ping_interval", c.Database.PingInterval)
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 || c.Database.ConnectRetries < 0 {
+		fail("database", "max_open_conns, max_idle_conns and connect_retries must not be negative")
+	}
+
+	// Rate limit
+	for group, rule := range c.RateLimit.Groups {
+		if !rule.valid() {
+			fail("rate_limit.groups."+group, "requests and period must be positive and burst must not be negative")
+		}
+	}
+	for _, override := range c.RateLimit.Principals {
+		if override.Principal == "" || !override.RateLimitRule.valid() {
+			fail("rate_limit.principals", "%q needs a principal, and requests and period must be positive", override.Principal)
+		}
+	}
+	nonNegative(fail, "rate_limit.cleanup_interval", c.RateLimit.CleanupInterval)
+
+	// Logging
+	if _, err := logger.ParseLevel(c.Logging.Level); err != nil {
+		fail("logging.level", "%v", err)
+	}
+	for component, level := range c.Logging.Levels {
+		if _, err := logger.ParseLevel(level); err != nil {
+			fail("logging.levels."+component, "%v", err)
+		}
+	}
+	if c.Logging.Format != "" && !oneOf(c.Logging.Format, validLogFormats) {
+		fail("logging.format", "must be one of %s, got %q", strings.Join(validLogFormats, ", "), c.Logging.Format)
+	}
+	nonNegative(fail, "logging.slow_query_threshold", c.Logging.SlowQueryThreshold)
+	if c.Logging.ELKEnabled {
+		if c.Logging.ELKHost == "" {
+			fail("logging.elk_host", "is required when logging.elk_enabled is true")
+		}
+		if c.Logging.ELKBatchSize < 1 || c.Logging.ELKBufferSize < 1 {
+			fail("logging", "elk_batch_size and elk_buffer_size must be positive")
+		}
+	}
+	if c.Logging.TraceExporter != "" && !oneOf(c.Logging.TraceExporter, validTraceExporters) {
+		fail("logging.trace_exporter", "must be one of %s, got %q", strings.Join(validTraceExporters, ", "), c.Logging.TraceExporter)
+	}
+	if c.Logging.TraceSampleRatio < 0 || c.Logging.TraceSampleRatio > 1 {
+		fail("logging.trace_sample_ratio", "must be between 0 and 1, got %v", c.Logging.TraceSampleRatio)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r RateLimitRule) valid() bool {
+	return r.Requests > 0 && r.Period > 0 && r.Burst >= 0
+}
+
+func nonNegative(fail func(key, format string, args ...interface{}), key string, d time.Duration) {
+	if d < 0 {
+		fail(key, "must not be negative, got %s", d)
+	}
+}
+
+func oneOf(value string, allowed []string) bool {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// isWeakSecret melaporkan apakah secret adalah nilai contoh/default yang dikenal
+func isWeakSecret(secret string) bool {
+	for _, weak := range weakSecrets {
+		if strings.EqualFold(secret, weak) {
+			return true
+		}
+	}
+	return false
+}
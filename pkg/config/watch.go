@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// reloadablePrefixes adalah key yang bisa berubah tanpa restart. Perubahan key
+// lain (database, port, jwt_secret, ...) hanya dicatat sebagai warning.
+var reloadablePrefixes = []string{
+	"logging.level",
+	"logging.format",
+	"logging.levels",
+	"logging.elk_",
+	"rate_limit.",
+}
+
+// WatchConfig memantau file config yang dibaca current (lihat Files). Saat ada
+// perubahan, config dimuat ulang dan divalidasi; jika gagal, config lama tetap
+// dipakai. onReload dipanggil dengan config baru hanya jika key yang bisa di-reload
+// berubah. Pemanggilan onReload tidak pernah berjalan bersamaan.
+func WatchConfig(current *Config, onReload func(*Config)) {
+	var mu sync.Mutex
+	reload := func(event fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		next, err := LoadConfig()
+		if err != nil {
+			log.Error("config reload failed, keeping current config", "file", event.Name, "error", err)
+			return
+		}
+
+		var applied, ignored []string
+		for _, key := range changedKeys(current, next) {
+			if reloadable(key) {
+				applied = append(applied, key)
+			} else {
+				ignored = append(ignored, key)
+			}
+		}
+		// current adalah config terakhir yang dibaca, agar setiap perubahan hanya dilaporkan sekali
+		current = next
+		if len(ignored) > 0 {
+			log.Warn("config changes require a restart", "keys", ignored)
+		}
+		if len(applied) == 0 {
+			return
+		}
+
+		onReload(next)
+		log.Info("config reloaded", "file", event.Name, "keys", applied)
+	}
+
+	for _, file := range current.files {
+		v := viper.New()
+		v.SetConfigFile(file)
+		v.OnConfigChange(reload)
+		v.WatchConfig()
+	}
+}
+
+func reloadable(key string) bool {
+	for _, prefix := range reloadablePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
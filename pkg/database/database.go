@@ -1,21 +1,332 @@
 package database
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
 	"fmt"
+	"net"
+	"os"
 	"project/pkg/config"
+	"project/pkg/logger"
+	"project/pkg/tracing"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/glebarez/sqlite"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// log adalah logger komponen "database" untuk seluruh package ini
+var log = logger.New("database")
+
+// Driver database yang didukung (nilai database.driver di config)
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+)
+
+// ConnectDB membuka koneksi database dan mengatur connection pool.
+// Jika database belum siap (misalnya container Postgres masih start), koneksi
+// dicoba ulang sebanyak database.connect_retries dengan backoff eksponensial.
 func ConnectDB(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
-		cfg.Database.Host, cfg.Database.User, cfg.Database.Password, cfg.Database.DBName, cfg.Database.Port)
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openWithRetry(dialector, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
 	}
+	configurePool(sqlDB, cfg)
+
+	// Setiap koneksi SQLite ":memory:" adalah database terpisah, jadi batasi ke satu
+	// koneksi yang tidak pernah ditutup karena idle atau umur
+	if dialector.Name() == DriverSQLite && isSQLiteMemory(cfg.Database.DBName) {
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	}
+
+	if len(cfg.Database.Replicas) > 0 {
+		if err := db.Use(openReplicas(cfg)); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+	}
+
+	// Span untuk setiap query yang dijalankan dalam context request yang di-trace
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
 
 	return db, nil
 }
+
+// Close menutup connection pool read replica (jika ada) lalu primary
+func Close(db *gorm.DB) error {
+	var errs []error
+	if resolver := ResolverOf(db); resolver != nil {
+		errs = append(errs, resolver.Close())
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	errs = append(errs, sqlDB.Close())
+	return errors.Join(errs...)
+}
+
+// configurePool menerapkan pengaturan connection pool dari config
+func configurePool(sqlDB *sql.DB, cfg *config.Config) {
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+}
+
+// openReplicas membuka connection pool untuk setiap DSN di database.replicas.
+// Replica yang belum bisa di-ping tetap dipakai tetapi ditandai tidak sehat sampai
+// Monitor berhasil mem-ping-nya; startup tidak ditahan oleh replica.
+func openReplicas(cfg *config.Config) *Resolver {
+	resolver := &Resolver{}
+	for i, dsn := range cfg.Database.Replicas {
+		// Nama dipakai di log dan /debug/vars, jadi DSN (berisi password) tidak ditampilkan
+		name := fmt.Sprintf("replica-%d", i+1)
+
+		dialector, err := dialectorFor(cfg.Database.Driver, dsn)
+		if err != nil {
+			log.Warn("skipping database replica", "replica", name, "error", err)
+			continue
+		}
+		// Ping dilakukan sendiri: gorm.Open menutup pool jika inisialisasi gagal,
+		// sedangkan replica yang sekadar belum bisa di-ping harus tetap bisa pulih
+		replicaDB, err := gorm.Open(dialector, &gorm.Config{
+			Logger:               logger.NewGormLogger(cfg.Logging.SlowQueryThreshold),
+			DisableAutomaticPing: true,
+		})
+		if err != nil {
+			log.Warn("skipping database replica", "replica", name, "error", err)
+			continue
+		}
+		sqlDB, err := replicaDB.DB()
+		if err != nil {
+			log.Warn("skipping database replica", "replica", name, "error", err)
+			continue
+		}
+		configurePool(sqlDB, cfg)
+
+		replica := &replica{name: name, db: sqlDB}
+		replica.healthy.Store(true)
+		replica.setHealth(sqlDB.Ping())
+		resolver.replicas = append(resolver.replicas, replica)
+	}
+	return resolver
+}
+
+// openWithRetry membuka koneksi (gorm.Open juga melakukan ping) dan mencoba ulang jika gagal
+func openWithRetry(dialector gorm.Dialector, cfg *config.Config) (*gorm.DB, error) {
+	backoff := cfg.Database.ConnectBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.NewGormLogger(cfg.Logging.SlowQueryThreshold)})
+		if err == nil {
+			return db, nil
+		}
+		// gorm.Open tidak menutup pool jika ping gagal
+		if db != nil {
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				sqlDB.Close()
+			}
+		}
+		if attempt > cfg.Database.ConnectRetries {
+			return nil, fmt.Errorf("connect to database after %d attempt(s): %w", attempt, err)
+		}
+
+		log.Warn("database not ready, retrying",
+			"attempt", attempt, "max_attempts", cfg.Database.ConnectRetries+1, "retry_in", backoff.String(), "error", err)
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if cfg.Database.ConnectMaxBackoff > 0 && backoff > cfg.Database.ConnectMaxBackoff {
+			backoff = cfg.Database.ConnectMaxBackoff
+		}
+	}
+}
+
+// Dialector memilih driver gorm dan membangun DSN sesuai database.driver
+func Dialector(cfg *config.Config) (gorm.Dialector, error) {
+	var dsn string
+	switch strings.ToLower(cfg.Database.Driver) {
+	case "", DriverPostgres:
+		dsn = PostgresDSN(cfg)
+	case DriverMySQL:
+		var err error
+		if dsn, err = MySQLDSN(cfg); err != nil {
+			return nil, err
+		}
+	case DriverSQLite:
+		dsn = SQLiteDSN(cfg)
+	}
+	return dialectorFor(cfg.Database.Driver, dsn)
+}
+
+// dialectorFor membuat dialector gorm untuk driver dengan DSN yang sudah jadi
+func dialectorFor(driver, dsn string) (gorm.Dialector, error) {
+	switch strings.ToLower(driver) {
+	case "", DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverMySQL:
+		return mysql.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// PostgresDSN membangun DSN key=value untuk Postgres, termasuk mode SSL dan sertifikat
+func PostgresDSN(cfg *config.Config) string {
+	db := cfg.Database
+	sslMode := db.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	params := [][2]string{
+		{"host", db.Host},
+		{"user", db.User},
+		{"password", db.Password},
+		{"dbname", db.DBName},
+		{"port", strconv.Itoa(db.Port)},
+		{"sslmode", sslMode},
+		{"sslrootcert", db.SSLRootCert},
+		{"sslcert", db.SSLCert},
+		{"sslkey", db.SSLKey},
+	}
+
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		if param[1] == "" && param[0] != "password" {
+			continue
+		}
+		parts = append(parts, param[0]+"="+quotePostgresValue(param[1]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quotePostgresValue meng-escape nilai yang kosong atau mengandung spasi/kutip
+func quotePostgresValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// MySQLDSN membangun DSN untuk MySQL. sslmode mengikuti penamaan Postgres:
+// disable, require (TLS tanpa verifikasi), verify-ca / verify-full (verifikasi
+// dengan sslrootcert, opsional client cert sslcert/sslkey).
+func MySQLDSN(cfg *config.Config) (string, error) {
+	db := cfg.Database
+	mysqlConfig := mysqlDriver.NewConfig()
+	mysqlConfig.User = db.User
+	mysqlConfig.Passwd = db.Password
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = net.JoinHostPort(db.Host, strconv.Itoa(db.Port))
+	mysqlConfig.DBName = db.DBName
+	mysqlConfig.ParseTime = true
+	mysqlConfig.Params = map[string]string{"charset": "utf8mb4"}
+
+	switch db.SSLMode {
+	case "", "disable":
+	case "require":
+		mysqlConfig.TLSConfig = "skip-verify"
+	case "verify-ca", "verify-full":
+		tlsConfig, err := mysqlTLSConfig(cfg)
+		if err != nil {
+			return "", err
+		}
+		if err := mysqlDriver.RegisterTLSConfig("custom", tlsConfig); err != nil {
+			return "", err
+		}
+		mysqlConfig.TLSConfig = "custom"
+	default:
+		return "", fmt.Errorf("unsupported sslmode %q for mysql", db.SSLMode)
+	}
+
+	return mysqlConfig.FormatDSN(), nil
+}
+
+// mysqlTLSConfig memuat CA dan client certificate untuk koneksi MySQL
+func mysqlTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	db := cfg.Database
+	tlsConfig := &tls.Config{ServerName: db.Host}
+
+	if db.SSLRootCert != "" {
+		pem, err := os.ReadFile(db.SSLRootCert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", db.SSLRootCert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if db.SSLCert != "" && db.SSLKey != "" {
+		cert, err := tls.LoadX509KeyPair(db.SSLCert, db.SSLKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// verify-ca hanya memeriksa rantai CA, bukan hostname
+	if db.SSLMode == "verify-ca" {
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			opts := x509.VerifyOptions{Roots: tlsConfig.RootCAs, Intermediates: x509.NewCertPool()}
+			for _, cert := range state.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := state.PeerCertificates[0].Verify(opts)
+			return err
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// SQLiteDSN memakai database.dbname sebagai path file (":memory:" untuk in-memory)
+// dan mengaktifkan foreign key
+func SQLiteDSN(cfg *config.Config) string {
+	path := cfg.Database.DBName
+	if isSQLiteMemory(path) {
+		path = ":memory:"
+	}
+	return path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+func isSQLiteMemory(path string) bool {
+	return path == "" || path == ":memory:"
+}
@@ -1,21 +1,433 @@
 package database
 
 import (
-	"log"
-	"project/internal/models"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-// MigrateDatabase creates tables for all models
-func MigrateDatabase(db *gorm.DB) error {
-	log.Println("Migrating database...")
+// Migrasi ditulis per driver di migrations/<driver>/
+//
+//go:embed migrations
+var migrationFiles embed.FS
 
-	// Auto-migrate the User model
-	if err := db.AutoMigrate(&models.User{}, &models.Role{}, &models.Permission{}, &models.UserRole{}, &models.RolePermission{}); err != nil {
+// migrationLockID adalah key advisory lock (pg_advisory_lock / GET_LOCK) agar
+// hanya satu proses yang menjalankan migrasi pada saat yang sama
+const migrationLockID int64 = 7209245130
+
+// migrationFileName: <version>_<name>.<up|down>.sql, contoh 0001_init.up.sql
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration adalah satu versi skema beserta SQL up dan down-nya
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus menunjukkan apakah sebuah migrasi sudah diterapkan
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// schemaMigration adalah baris pada tabel schema_migrations
+type schemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// createSchemaMigrationsSQL per driver
+var createSchemaMigrationsSQL = map[string]string{
+	DriverPostgres: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	applied_at timestamptz NOT NULL
+)`,
+	DriverMySQL: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint unsigned NOT NULL PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	applied_at datetime(3) NOT NULL
+)`,
+	DriverSQLite: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version integer PRIMARY KEY,
+	name text NOT NULL,
+	applied_at datetime NOT NULL
+)`,
+}
+
+// Migrator menerapkan migrasi SQL yang di-embed ke dalam binary
+type Migrator struct {
+	db         *gorm.DB
+	driver     string
+	migrations []Migration
+	observer   MigrationObserver
+}
+
+// MigrationObserver menerima durasi dan hasil setiap migrasi (misalnya untuk metrics).
+// direction berisi "up" atau "down".
+type MigrationObserver interface {
+	ObserveMigration(direction string, version uint, name string, duration time.Duration, err error)
+}
+
+// SetObserver memasang observer untuk migrasi berikutnya
+func (m *Migrator) SetObserver(observer MigrationObserver) {
+	m.observer = observer
+}
+
+func (m *Migrator) observe(direction string, migration Migration, start time.Time, err error) {
+	if m.observer != nil {
+		m.observer.ObserveMigration(direction, migration.Version, migration.Name, time.Since(start), err)
+	}
+}
+
+// NewMigrator membuat Migrator dengan migrasi dari folder migrations/<driver>
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	driver := db.Dialector.Name()
+	if _, ok := createSchemaMigrationsSQL[driver]; !ok {
+		return nil, fmt.Errorf("migrations are not supported for driver %q", driver)
+	}
+
+	migrations, err := loadMigrations(migrationFiles, path.Join("migrations", driver))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, driver: driver, migrations: migrations}, nil
+}
+
+// loadMigrations membaca file migrasi dari dir dan mengurutkannya berdasarkan versi
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah diterapkan
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.rollback(conn, migration); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// To menerapkan atau membatalkan migrasi hingga skema berada pada version.
+// version 0 membatalkan semua migrasi.
+func (m *Migrator) To(ctx context.Context, version uint) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		// Batalkan migrasi di atas target, dari yang terbaru
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				if err := m.rollback(conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Terapkan migrasi hingga target, dari yang terlama
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				if err := m.apply(conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// ErrNotInitialised - Tabel schema_migrations belum ada (migrate up belum pernah dijalankan)
+var ErrNotInitialised = errors.New("migrations not initialised: schema_migrations table does not exist")
+
+// Status mengembalikan status setiap migrasi yang dikenal. Status hanya membaca
+// database (aman untuk readiness probe) dan mengembalikan ErrNotInitialised jika
+// tabel schema_migrations belum ada.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	// Status dibaca dari primary, bukan replica yang mungkin tertinggal
+	conn := m.db.WithContext(UsePrimary(ctx))
+	if !conn.Migrator().HasTable(&schemaMigration{}) {
+		return nil, ErrNotInitialised
+	}
+	applied, err := appliedMigrations(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending mengembalikan migrasi yang belum diterapkan
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) find(version uint) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// withLock menjalankan fn pada satu koneksi yang memegang advisory lock migrasi.
+// SQLite tidak membutuhkan lock karena hanya mengizinkan satu writer.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		var lockSQL, unlockSQL string
+		switch m.driver {
+		case DriverPostgres:
+			lockSQL, unlockSQL = "SELECT pg_advisory_lock(?)", "SELECT pg_advisory_unlock(?)"
+		case DriverMySQL:
+			// GET_LOCK memakai nama string; -1 berarti tunggu tanpa batas waktu
+			lockSQL, unlockSQL = "SELECT GET_LOCK(CONCAT('schema_migrations_', ?), -1)", "SELECT RELEASE_LOCK(CONCAT('schema_migrations_', ?))"
+		}
+
+		if lockSQL != "" {
+			if err := conn.Exec(lockSQL, migrationLockID).Error; err != nil {
+				return fmt.Errorf("acquire migration lock: %w", err)
+			}
+			// Lock harus dilepas walaupun ctx sudah dibatalkan
+			defer conn.WithContext(context.Background()).Exec(unlockSQL, migrationLockID)
+		}
+
+		if err := conn.Exec(createSchemaMigrationsSQL[m.driver]).Error; err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) apply(conn *gorm.DB, migration Migration) error {
+	log.Info("applying migration", "version", migration.Version, "name", migration.Name)
+	start := time.Now()
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := execStatements(tx, migration.Up); err != nil {
+			return err
+		}
+		return tx.Create(&schemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	m.observe("up", migration, start, err)
+	if err != nil {
+		return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+	}
+
+	log.Info("migration applied", "version", migration.Version, "name", migration.Name, "duration_ms", time.Since(start).Milliseconds())
+	return nil
+}
+
+func (m *Migrator) rollback(conn *gorm.DB, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+	}
+	log.Info("rolling back migration", "version", migration.Version, "name", migration.Name)
+	start := time.Now()
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := execStatements(tx, migration.Down); err != nil {
+			return err
+		}
+		return tx.Delete(&schemaMigration{}, "version = ?", migration.Version).Error
+	})
+	m.observe("down", migration, start, err)
+	if err != nil {
+		return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// execStatements menjalankan file migrasi statement per statement, karena tidak
+// semua driver (misalnya MySQL) menerima beberapa statement dalam satu Exec.
+// Setiap statement harus diakhiri ";" di akhir baris.
+// Catatan: DDL di MySQL tidak transactional, jadi migrasi yang gagal di tengah
+// harus diperbaiki manual.
+func execStatements(tx *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements memecah script SQL per ";" di akhir baris dan membuang
+// baris komentar "--"
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// appliedMigrations membaca isi schema_migrations
+func appliedMigrations(conn *gorm.DB) (map[uint]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := conn.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// MigrateDatabase menerapkan semua migrasi yang belum diterapkan.
+// observer boleh nil.
+func MigrateDatabase(db *gorm.DB, observer MigrationObserver) error {
+	log.Info("migrating database")
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	migrator.SetObserver(observer)
+	if err := migrator.Up(context.Background()); err != nil {
 		return err
 	}
 
-	log.Println("Database migration completed.")
+	log.Info("database migration completed")
 	return nil
 }
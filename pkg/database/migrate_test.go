@@ -0,0 +1,66 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"project/pkg/config"
+	"project/pkg/database"
+	"project/pkg/health"
+	"testing"
+)
+
+func TestMigratorStatusIsReadOnly(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Database.Driver = database.DriverSQLite
+	cfg.Database.DBName = ":memory:"
+	db, err := database.ConnectDB(cfg)
+	if err != nil {
+		t.Fatalf("connect to sqlite: %v", err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	ctx := context.Background()
+	check := health.MigrationsCheck(migrator)
+
+	// Sebelum migrate up: status dan readiness check melaporkan belum diinisialisasi
+	// tanpa membuat tabel schema_migrations
+	for i := 0; i < 2; i++ {
+		if _, err := migrator.Status(ctx); !errors.Is(err, database.ErrNotInitialised) {
+			t.Fatalf("Status before Up = %v, want ErrNotInitialised", err)
+		}
+		if err := check.Check(ctx); !errors.Is(err, database.ErrNotInitialised) {
+			t.Fatalf("MigrationsCheck before Up = %v, want ErrNotInitialised", err)
+		}
+	}
+	if db.Migrator().HasTable("schema_migrations") {
+		t.Fatal("schema_migrations was created by Status")
+	}
+
+	if err := migrator.Down(ctx, 1); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	// Tabel sudah dibuat oleh Down, tapi belum ada migrasi yang diterapkan
+	if err := check.Check(ctx); err == nil || errors.Is(err, database.ErrNotInitialised) {
+		t.Fatalf("MigrationsCheck with pending migrations = %v, want a pending error", err)
+	}
+
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status after Up: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied {
+			t.Errorf("migration %04d_%s is not applied after Up", status.Version, status.Name)
+		}
+	}
+	if err := check.Check(ctx); err != nil {
+		t.Errorf("MigrationsCheck after Up = %v, want nil", err)
+	}
+}
@@ -0,0 +1,53 @@
+-- Skema awal untuk MySQL. UUID disimpan sebagai char(36).
+CREATE TABLE IF NOT EXISTS users (
+    id char(36) NOT NULL PRIMARY KEY,
+    username varchar(255) UNIQUE,
+    password varchar(255) NOT NULL UNIQUE,
+    role varchar(255) NOT NULL,
+    version bigint unsigned NOT NULL DEFAULT 1,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3)
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    name varchar(255) NOT NULL UNIQUE,
+    created_at datetime(3),
+    updated_at datetime(3)
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    name varchar(255) NOT NULL UNIQUE,
+    created_at datetime(3),
+    updated_at datetime(3)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id char(36) NOT NULL,
+    role_id bigint unsigned NOT NULL,
+    created_at datetime(3),
+    updated_at datetime(3),
+    PRIMARY KEY (user_id, role_id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_permissions (
+    user_id char(36) NOT NULL,
+    permission_id bigint unsigned NOT NULL,
+    PRIMARY KEY (user_id, permission_id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id bigint unsigned NOT NULL,
+    permission_id bigint unsigned NOT NULL,
+    created_at datetime(3),
+    updated_at datetime(3),
+    PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
@@ -0,0 +1,23 @@
+-- Audit log perubahan administratif. Append-only: setiap baris menyimpan hash
+-- baris sebelumnya (prev_hash) sehingga perubahan atau penghapusan terdeteksi.
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    actor_id varchar(255) NOT NULL,
+    actor_username varchar(255) NOT NULL,
+    action varchar(64) NOT NULL,
+    target_type varchar(64) NOT NULL,
+    target_id varchar(255) NOT NULL,
+    changes text NOT NULL,
+    ip varchar(64) NOT NULL,
+    request_id varchar(128) NOT NULL,
+    created_at datetime(3) NOT NULL,
+    prev_hash char(64) NOT NULL UNIQUE,
+    hash char(64) NOT NULL UNIQUE,
+    INDEX idx_audit_logs_actor_id (actor_id),
+    INDEX idx_audit_logs_target_id (target_id),
+    INDEX idx_audit_logs_created_at (created_at)
+);
+
+-- Tolak UPDATE dan DELETE di level database
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE ON audit_logs FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+CREATE TRIGGER audit_logs_no_delete BEFORE DELETE ON audit_logs FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
@@ -0,0 +1,51 @@
+-- Skema awal, setara dengan hasil AutoMigrate sebelumnya.
+-- Memakai IF NOT EXISTS supaya database lama (dibuat oleh AutoMigrate) tetap bisa di-baseline.
+CREATE TABLE IF NOT EXISTS users (
+    id uuid PRIMARY KEY,
+    username text UNIQUE,
+    password text NOT NULL UNIQUE,
+    role text NOT NULL,
+    version bigint NOT NULL DEFAULT 1,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+-- Kolom version ditambahkan setelah skema AutoMigrate pertama
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS roles (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id bigint NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_permissions (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    permission_id bigint NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id bigint NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id bigint NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (role_id, permission_id)
+);
@@ -0,0 +1,25 @@
+-- Audit log perubahan administratif. Append-only: setiap baris menyimpan hash
+-- baris sebelumnya (prev_hash) sehingga perubahan atau penghapusan terdeteksi.
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id bigserial PRIMARY KEY,
+    actor_id text NOT NULL,
+    actor_username text NOT NULL,
+    action text NOT NULL,
+    target_type text NOT NULL,
+    target_id text NOT NULL,
+    changes text NOT NULL,
+    ip text NOT NULL,
+    request_id text NOT NULL,
+    created_at timestamptz NOT NULL,
+    prev_hash char(64) NOT NULL UNIQUE,
+    hash char(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target_id ON audit_logs (target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+-- Tolak UPDATE, DELETE dan TRUNCATE di level database
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$ BEGIN RAISE EXCEPTION 'audit_logs is append-only'; END; $$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_logs_no_modify BEFORE UPDATE OR DELETE ON audit_logs FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+CREATE TRIGGER audit_logs_no_truncate BEFORE TRUNCATE ON audit_logs FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
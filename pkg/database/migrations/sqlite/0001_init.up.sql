@@ -0,0 +1,47 @@
+-- Skema awal untuk SQLite (development dan test). UUID disimpan sebagai text.
+CREATE TABLE IF NOT EXISTS users (
+    id text NOT NULL PRIMARY KEY,
+    username text UNIQUE,
+    password text NOT NULL UNIQUE,
+    role text NOT NULL,
+    version integer NOT NULL DEFAULT 1,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL UNIQUE,
+    created_at datetime,
+    updated_at datetime
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL UNIQUE,
+    created_at datetime,
+    updated_at datetime
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id text NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id integer NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_permissions (
+    user_id text NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    permission_id integer NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id integer NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id integer NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (role_id, permission_id)
+);
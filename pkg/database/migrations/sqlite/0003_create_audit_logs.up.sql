@@ -0,0 +1,24 @@
+-- Audit log perubahan administratif. Append-only: setiap baris menyimpan hash
+-- baris sebelumnya (prev_hash) sehingga perubahan atau penghapusan terdeteksi.
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    actor_id text NOT NULL,
+    actor_username text NOT NULL,
+    action text NOT NULL,
+    target_type text NOT NULL,
+    target_id text NOT NULL,
+    changes text NOT NULL,
+    ip text NOT NULL,
+    request_id text NOT NULL,
+    created_at datetime NOT NULL,
+    prev_hash text NOT NULL UNIQUE,
+    hash text NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target_id ON audit_logs (target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+-- Tolak UPDATE dan DELETE di level database
+CREATE TRIGGER IF NOT EXISTS audit_logs_no_update BEFORE UPDATE ON audit_logs BEGIN SELECT RAISE(ABORT, 'audit_logs is append-only'); END;
+CREATE TRIGGER IF NOT EXISTS audit_logs_no_delete BEFORE DELETE ON audit_logs BEGIN SELECT RAISE(ABORT, 'audit_logs is append-only'); END;
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Monitor melakukan ping berkala ke database dan menyimpan hasil terakhirnya
+type Monitor struct {
+	db       *sql.DB
+	resolver *Resolver
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu        sync.RWMutex
+	lastErr   error
+	checkedAt time.Time
+}
+
+// MonitorStatus adalah hasil ping terakhir beserta statistik connection pool
+type MonitorStatus struct {
+	Healthy   bool        `json:"healthy"`
+	Error     string      `json:"error,omitempty"`
+	CheckedAt time.Time   `json:"checked_at"`
+	Pool      sql.DBStats `json:"pool"`
+	// Replicas berisi status read replica (jika database.replicas diisi)
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+}
+
+// NewMonitor membuat Monitor untuk connection pool milik db, termasuk read replica-nya
+func NewMonitor(db *gorm.DB, interval time.Duration) (*Monitor, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return &Monitor{db: sqlDB, resolver: ResolverOf(db), interval: interval}, nil
+}
+
+// Start menjalankan ping berkala sampai ctx dibatalkan atau Stop dipanggil.
+// Perubahan status (sehat -> gagal dan sebaliknya) dicatat ke log.
+func (m *Monitor) Start(ctx context.Context) {
+	if m.interval <= 0 {
+		return
+	}
+
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		m.Ping(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Ping(ctx)
+			}
+		}
+	}()
+}
+
+// Stop menghentikan ping berkala dan menunggu ping yang sedang berjalan selesai
+func (m *Monitor) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.done
+}
+
+// Ping memeriksa koneksi database sekarang dan menyimpan hasilnya.
+// Replica yang gagal di-ping tidak dipakai untuk query baca sampai pulih.
+func (m *Monitor) Ping(ctx context.Context) error {
+	if m.resolver != nil {
+		m.resolver.Ping(ctx)
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	err := m.db.PingContext(pingCtx)
+
+	m.mu.Lock()
+	wasHealthy := m.lastErr == nil
+	m.lastErr = err
+	m.checkedAt = time.Now()
+	m.mu.Unlock()
+
+	switch {
+	case err != nil && wasHealthy:
+		log.ErrorContext(ctx, "database ping failed", "error", err)
+	case err == nil && !wasHealthy:
+		log.InfoContext(ctx, "database connection recovered")
+	}
+	return err
+}
+
+// Status mengembalikan hasil ping terakhir dan statistik connection pool
+func (m *Monitor) Status() MonitorStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := MonitorStatus{
+		Healthy:   m.lastErr == nil,
+		CheckedAt: m.checkedAt,
+		Pool:      m.db.Stats(),
+	}
+	if m.lastErr != nil {
+		status.Error = m.lastErr.Error()
+	}
+	if m.resolver != nil {
+		status.Replicas = m.resolver.Status()
+	}
+	return status
+}
@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// resolverName adalah nama plugin di gorm.Config.Plugins
+const resolverName = "app:replica_resolver"
+
+// replica adalah satu read replica beserta status kesehatannya
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+	lastErr atomic.Pointer[string]
+}
+
+// Resolver adalah plugin gorm yang mengarahkan query baca (SELECT) ke read replica
+// secara round-robin, sedangkan query tulis dan transaksi tetap ke primary.
+// Replica yang gagal di-ping dilewati; jika semua replica tidak sehat, query baca
+// kembali ke primary.
+type Resolver struct {
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// ReplicaStatus adalah kesehatan dan statistik connection pool satu replica
+type ReplicaStatus struct {
+	Name    string      `json:"name"`
+	Healthy bool        `json:"healthy"`
+	Error   string      `json:"error,omitempty"`
+	Pool    sql.DBStats `json:"pool"`
+}
+
+// Name - nama plugin gorm
+func (r *Resolver) Name() string {
+	return resolverName
+}
+
+// Initialize mendaftarkan callback pemilih koneksi sebelum query dijalankan, dan
+// callback penanda "read your writes" setelah query tulis
+func (r *Resolver) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("*").Register(resolverName, r.switchReplica); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("*").Register(resolverName, r.switchReplica); err != nil {
+		return err
+	}
+
+	callbacks := db.Callback()
+	for _, register := range []func(name string, fn func(*gorm.DB)) error{
+		callbacks.Create().After("*").Register,
+		callbacks.Update().After("*").Register,
+		callbacks.Delete().After("*").Register,
+		callbacks.Raw().After("*").Register,
+	} {
+		if err := register(resolverName+":mark_write", markWrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// switchReplica mengganti koneksi statement ke replica jika query boleh dibaca dari replica
+func (r *Resolver) switchReplica(db *gorm.DB) {
+	if db.Error != nil || !r.readable(db) {
+		return
+	}
+	if pool := r.pick(); pool != nil {
+		db.Statement.ConnPool = pool
+	}
+}
+
+// readable memeriksa apakah statement boleh diarahkan ke replica
+func (r *Resolver) readable(db *gorm.DB) bool {
+	// Transaksi dan koneksi khusus (db.Connection) memakai ConnPool selain pool utama
+	if db.Statement.ConnPool != db.Config.ConnPool {
+		return false
+	}
+	// SELECT ... FOR UPDATE / FOR SHARE harus ke primary
+	if _, locking := db.Statement.Clauses["FOR"]; locking {
+		return false
+	}
+	// Raw query yang bukan SELECT (misalnya INSERT ... RETURNING lewat Raw().Scan())
+	if sql := strings.TrimSpace(db.Statement.SQL.String()); sql != "" &&
+		!strings.HasPrefix(strings.ToUpper(sql), "SELECT") {
+		return false
+	}
+	return !usesPrimary(db.Statement.Context)
+}
+
+// pick memilih replica sehat berikutnya (round-robin); nil jika tidak ada yang sehat
+func (r *Resolver) pick() *sql.DB {
+	count := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := uint64(0); i < count; i++ {
+		candidate := r.replicas[(start+i)%count]
+		if candidate.healthy.Load() {
+			return candidate.db
+		}
+	}
+	return nil
+}
+
+// Ping memeriksa semua replica dan memperbarui status kesehatannya.
+// Perubahan status dicatat ke log.
+func (r *Resolver) Ping(ctx context.Context) {
+	for _, replica := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := replica.db.PingContext(pingCtx)
+		cancel()
+		replica.setHealth(err)
+	}
+}
+
+// Status mengembalikan kesehatan dan statistik pool semua replica
+func (r *Resolver) Status() []ReplicaStatus {
+	statuses := make([]ReplicaStatus, 0, len(r.replicas))
+	for _, replica := range r.replicas {
+		status := ReplicaStatus{
+			Name:    replica.name,
+			Healthy: replica.healthy.Load(),
+			Pool:    replica.db.Stats(),
+		}
+		if lastErr := replica.lastErr.Load(); lastErr != nil {
+			status.Error = *lastErr
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Pools mengembalikan connection pool setiap replica berdasarkan namanya
+func (r *Resolver) Pools() map[string]*sql.DB {
+	pools := make(map[string]*sql.DB, len(r.replicas))
+	for _, replica := range r.replicas {
+		pools[replica.name] = replica.db
+	}
+	return pools
+}
+
+// Close menutup connection pool semua replica
+func (r *Resolver) Close() error {
+	var errs []error
+	for _, replica := range r.replicas {
+		errs = append(errs, replica.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (r *replica) setHealth(err error) {
+	wasHealthy := r.healthy.Swap(err == nil)
+	if err != nil {
+		message := err.Error()
+		r.lastErr.Store(&message)
+	} else {
+		r.lastErr.Store(nil)
+	}
+
+	switch {
+	case err != nil && wasHealthy:
+		log.Warn("database replica unhealthy, reads fall back to other replicas or primary", "replica", r.name, "error", err)
+	case err == nil && !wasHealthy:
+		log.Info("database replica recovered", "replica", r.name)
+	}
+}
+
+// ResolverOf mengembalikan Resolver yang terpasang di db (nil jika tidak ada replica)
+func ResolverOf(db *gorm.DB) *Resolver {
+	if resolver, ok := db.Config.Plugins[resolverName].(*Resolver); ok {
+		return resolver
+	}
+	return nil
+}
+
+// primaryKey - Key context untuk penanda "baca dari primary"
+type primaryKey struct{}
+
+// UsePrimary memaksa semua query dengan ctx ini (termasuk baca) ke primary
+func UsePrimary(ctx context.Context) context.Context {
+	flag := &atomic.Bool{}
+	flag.Store(true)
+	return context.WithValue(ctx, primaryKey{}, flag)
+}
+
+// WithReadYourWrites memasang penanda di ctx: setelah ada query tulis dengan ctx ini,
+// query baca berikutnya juga diarahkan ke primary sehingga tidak terkena replication lag.
+// Dipasang per request oleh middleware.ReadYourWrites.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(primaryKey{}).(*atomic.Bool); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, primaryKey{}, &atomic.Bool{})
+}
+
+func usesPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	flag, ok := ctx.Value(primaryKey{}).(*atomic.Bool)
+	return ok && flag.Load()
+}
+
+// markWrite menandai ctx statement setelah query tulis berhasil
+func markWrite(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Context == nil {
+		return
+	}
+	if flag, ok := db.Statement.Context.Value(primaryKey{}).(*atomic.Bool); ok {
+		flag.Store(true)
+	}
+}
@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"project/pkg/database"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DatabaseCheck mem-ping connection pool primary
+func DatabaseCheck(db *gorm.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// MigrationsCheck gagal jika masih ada migrasi yang belum dijalankan
+func MigrationsCheck(migrator *database.Migrator) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migration(s), latest is %04d_%s", len(pending), pending[len(pending)-1].Version, pending[len(pending)-1].Name)
+		}
+		return nil
+	})
+}
+
+// ElasticsearchCheck memeriksa /_cluster/health; status "red" dianggap gagal.
+// host boleh dengan atau tanpa skema (default http://).
+func ElasticsearchCheck(client *http.Client, host string) Checker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	url := strings.TrimRight(host, "/")
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+	url += "/_cluster/health"
+
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("elasticsearch returned %s", resp.Status)
+		}
+		var clusterHealth struct {
+			Status string `json:"status"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&clusterHealth); err != nil {
+			return err
+		}
+		if clusterHealth.Status == "red" {
+			return fmt.Errorf("elasticsearch cluster status is red")
+		}
+		return nil
+	})
+}
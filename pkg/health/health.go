@@ -0,0 +1,158 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status hasil pemeriksaan
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Checker memeriksa satu dependency (database, migrasi, Elasticsearch, ...)
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc mengubah fungsi biasa menjadi Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check memanggil f(ctx)
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult adalah hasil satu pemeriksaan
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	// Critical: jika false, kegagalan dilaporkan tetapi tidak membuat service tidak siap
+	Critical bool `json:"critical"`
+}
+
+// Report adalah hasil semua pemeriksaan; Status "fail" jika ada check critical yang gagal
+type Report struct {
+	Status    string                 `json:"status"`
+	CheckedAt time.Time              `json:"checked_at"`
+	Checks    map[string]CheckResult `json:"checks"`
+}
+
+// Healthy mengembalikan true jika semua check critical berhasil
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+type registeredCheck struct {
+	name     string
+	checker  Checker
+	critical bool
+}
+
+// Registry menyimpan daftar Checker yang dijalankan oleh endpoint readiness
+type Registry struct {
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+
+	mu     sync.RWMutex
+	checks []registeredCheck
+}
+
+// NewRegistry membuat Registry; setiap check dibatasi timeout (0 berarti tanpa batas)
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// SetShuttingDown menandai service sedang berhenti: /readyz langsung melaporkan
+// tidak siap agar orchestrator/load balancer berhenti mengirim request baru
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Register menambahkan check critical: jika gagal, service dianggap tidak siap
+func (r *Registry) Register(name string, checker Checker) {
+	r.add(name, checker, true)
+}
+
+// RegisterOptional menambahkan check yang hanya dilaporkan, tanpa memengaruhi status
+func (r *Registry) RegisterOptional(name string, checker Checker) {
+	r.add(name, checker, false)
+}
+
+func (r *Registry) add(name string, checker Checker, critical bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Nama yang sama menggantikan check sebelumnya
+	for i, check := range r.checks {
+		if check.name == name {
+			r.checks[i] = registeredCheck{name: name, checker: checker, critical: critical}
+			return
+		}
+	}
+	r.checks = append(r.checks, registeredCheck{name: name, checker: checker, critical: critical})
+	sort.Slice(r.checks, func(i, j int) bool { return r.checks[i].name < r.checks[j].name })
+}
+
+// Run menjalankan semua check secara paralel dan mengumpulkan hasilnya
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := append([]registeredCheck(nil), r.checks...)
+	r.mu.RUnlock()
+
+	report := Report{
+		Status:    StatusOK,
+		CheckedAt: time.Now(),
+		Checks:    make(map[string]CheckResult, len(checks)),
+	}
+	if r.shuttingDown.Load() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = CheckResult{Status: StatusFail, Error: "service is shutting down", Critical: true}
+		return report
+	}
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check registeredCheck) {
+			defer wg.Done()
+			results[i] = r.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for i, check := range checks {
+		report.Checks[check.name] = results[i]
+		if results[i].Critical && results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, check registeredCheck) CheckResult {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check.checker.Check(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Critical:  check.critical,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale dipakai jika client tidak meminta bahasa yang didukung
+const DefaultLocale = "en"
+
+// Katalog pesan per locale, satu file JSON datar (key -> pesan) per bahasa.
+// Placeholder ditulis {nama} dan diisi lewat params di Translate.
+//
+//go:embed locales/*.json
+var catalogFiles embed.FS
+
+// catalogs - Pesan per locale, dimuat sekali saat package diinisialisasi
+var catalogs = mustLoadCatalogs()
+
+func mustLoadCatalogs() map[string]map[string]string {
+	files, err := catalogFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	result := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := catalogFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", file.Name(), err))
+		}
+		result[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	if _, ok := result[DefaultLocale]; !ok {
+		panic("i18n: missing catalog for default locale " + DefaultLocale)
+	}
+	return result
+}
+
+// Locales mengembalikan semua locale yang punya katalog, terurut
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Supported melaporkan apakah ada katalog untuk locale
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Has melaporkan apakah key ada di katalog default (semua katalog memakai key yang sama)
+func Has(key string) bool {
+	_, ok := catalogs[DefaultLocale][key]
+	return ok
+}
+
+// Translate mengembalikan pesan key dalam locale, dengan fallback ke DefaultLocale
+// lalu ke key itu sendiri. params berisi pasangan nama dan nilai placeholder,
+// contoh Translate("id", "field.taken", "field", "username").
+func Translate(locale, key string, params ...string) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		if message, ok = catalogs[DefaultLocale][key]; !ok {
+			return key
+		}
+	}
+	if len(params) < 2 {
+		return message
+	}
+
+	pairs := make([]string, 0, len(params))
+	for i := 0; i+1 < len(params); i += 2 {
+		pairs = append(pairs, "{"+params[i]+"}", params[i+1])
+	}
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+// T menerjemahkan key dengan locale dari ctx (lihat WithLocale)
+func T(ctx context.Context, key string, params ...string) string {
+	return Translate(FromContext(ctx), key, params...)
+}
+
+// localeKey - Key context untuk locale request
+type localeKey struct{}
+
+// WithLocale menyimpan locale ke ctx
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext mengembalikan locale dari ctx, atau DefaultLocale jika tidak ada
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Negotiate memilih locale yang didukung dari header Accept-Language, mengikuti
+// urutan q tertinggi; "id-ID" cocok dengan "id". Mengembalikan DefaultLocale
+// jika tidak ada yang cocok.
+func Negotiate(acceptLanguage string) string {
+	type language struct {
+		tag string
+		q   float64
+	}
+	var languages []language
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			languages = append(languages, language{strings.ToLower(tag), q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].q > languages[j].q })
+
+	for _, lang := range languages {
+		if Supported(lang.tag) {
+			return lang.tag
+		}
+		if base, _, ok := strings.Cut(lang.tag, "-"); ok && Supported(base) {
+			return base
+		}
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,330 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ElasticsearchOptions mengatur pengiriman log ke Elasticsearch (bulk API)
+type ElasticsearchOptions struct {
+	// URL Elasticsearch, boleh tanpa skema (contoh: "localhost:9200")
+	URL string
+	// Index tujuan; boleh berisi layout tanggal Go di dalam kurung kurawal,
+	// contoh "app-logs-{2006.01.02}" menjadi index harian
+	Index string
+	// BatchSize adalah jumlah record maksimal per bulk request
+	BatchSize int
+	// FlushInterval adalah jeda maksimal sebelum batch yang belum penuh dikirim
+	FlushInterval time.Duration
+	// BufferSize membatasi jumlah record yang menunggu dikirim (di memori).
+	// Jika penuh, record baru dibuang dan dihitung di Dropped; logging tidak pernah memblokir request.
+	BufferSize int
+	// MaxRetries adalah jumlah percobaan ulang untuk error jaringan, 429 dan 5xx
+	MaxRetries int
+	// RetryBackoff adalah jeda awal percobaan ulang (berlipat ganda setiap kali)
+	RetryBackoff time.Duration
+	// Client HTTP; default client dengan timeout 10 detik
+	Client *http.Client
+}
+
+// SinkStats adalah counter pengiriman log
+type SinkStats struct {
+	Name string `json:"name"`
+	// Queued adalah jumlah record yang sedang menunggu di buffer
+	Queued int `json:"queued"`
+	// Sent adalah jumlah record yang diterima Elasticsearch
+	Sent uint64 `json:"sent"`
+	// Dropped adalah jumlah record yang dibuang karena buffer penuh atau sink sudah ditutup
+	Dropped uint64 `json:"dropped"`
+	// Failed adalah jumlah record yang gagal dikirim setelah semua percobaan ulang
+	Failed uint64 `json:"failed"`
+	// Retries adalah jumlah bulk request yang diulang
+	Retries   uint64 `json:"retries"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// ElasticsearchSink adalah io.Writer yang mengumpulkan record log JSON (satu record
+// per Write, seperti yang ditulis slog.JSONHandler) lalu mengirimnya secara
+// asinkron ke Elasticsearch dengan bulk request
+type ElasticsearchSink struct {
+	opts    ElasticsearchOptions
+	bulkURL string
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []byte
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	sent, dropped, failed, retries atomic.Uint64
+	lastErr                        atomic.Pointer[string]
+}
+
+// NewElasticsearchSink membuat sink dan menjalankan worker pengirimnya.
+// Panggil Close untuk mengirim sisa buffer sebelum aplikasi berhenti.
+func NewElasticsearchSink(opts ElasticsearchOptions) *ElasticsearchSink {
+	if opts.Index == "" {
+		opts.Index = "app-logs"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 2 * time.Second
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 10000
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 500 * time.Millisecond
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	url := strings.TrimRight(opts.URL, "/")
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &ElasticsearchSink{
+		opts:    opts,
+		bulkURL: url + "/_bulk",
+		queue:   make(chan []byte, opts.BufferSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write memasukkan satu record ke buffer tanpa menunggu pengiriman.
+// Tidak pernah mengembalikan error agar kegagalan sink tidak mengganggu output log lain.
+func (s *ElasticsearchSink) Write(p []byte) (int, error) {
+	record := bytes.TrimSpace(p)
+	if len(record) == 0 {
+		return len(p), nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.dropped.Add(1)
+		return len(p), nil
+	}
+
+	select {
+	case s.queue <- append([]byte(nil), record...):
+	default:
+		s.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Close berhenti menerima record, mengirim sisa buffer, lalu menunggu worker selesai.
+// Jika ctx berakhir lebih dulu, pengiriman dibatalkan dan sisa record dihitung gagal.
+func (s *ElasticsearchSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+		return ctx.Err()
+	}
+}
+
+// Stats mengembalikan counter pengiriman saat ini
+func (s *ElasticsearchSink) Stats() SinkStats {
+	stats := SinkStats{
+		Name:    "elasticsearch",
+		Queued:  len(s.queue),
+		Sent:    s.sent.Load(),
+		Dropped: s.dropped.Load(),
+		Failed:  s.failed.Load(),
+		Retries: s.retries.Load(),
+	}
+	if lastErr := s.lastErr.Load(); lastErr != nil {
+		stats.LastError = *lastErr
+	}
+	return stats
+}
+
+// run mengumpulkan record menjadi batch dan mengirimnya ketika batch penuh,
+// FlushInterval terlewati, atau buffer ditutup
+func (s *ElasticsearchSink) run() {
+	defer close(s.done)
+	defer s.cancel()
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.opts.BatchSize)
+	for {
+		select {
+		case record, ok := <-s.queue:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= s.opts.BatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// flush mengirim batch dengan percobaan ulang. Record yang ditolak Elasticsearch
+// karena kelebihan beban (429) ikut dicoba ulang; penolakan lain dihitung gagal.
+func (s *ElasticsearchSink) flush(batch [][]byte) {
+	backoff := s.opts.RetryBackoff
+	for attempt := 0; len(batch) > 0; attempt++ {
+		if attempt > 0 {
+			if attempt > s.opts.MaxRetries || s.ctx.Err() != nil {
+				s.failed.Add(uint64(len(batch)))
+				return
+			}
+			s.retries.Add(1)
+			select {
+			case <-time.After(backoff):
+			case <-s.ctx.Done():
+				s.failed.Add(uint64(len(batch)))
+				return
+			}
+			backoff *= 2
+		}
+
+		retry, err := s.send(batch)
+		if err != nil {
+			message := err.Error()
+			s.lastErr.Store(&message)
+		}
+		batch = retry
+	}
+}
+
+// send mengirim satu bulk request dan mengembalikan record yang perlu dicoba ulang
+func (s *ElasticsearchSink) send(batch [][]byte) ([][]byte, error) {
+	index := s.index(time.Now())
+	action := []byte(`{"create":{"_index":` + jsonString(index) + `}}` + "\n")
+
+	var body bytes.Buffer
+	for _, record := range batch {
+		body.Write(action)
+		body.Write(record)
+		body.WriteByte('\n')
+	}
+
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.bulkURL, &body)
+	if err != nil {
+		s.failed.Add(uint64(len(batch)))
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return batch, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		io.Copy(io.Discard, resp.Body)
+		return batch, fmt.Errorf("elasticsearch bulk returned %s", resp.Status)
+	case resp.StatusCode >= http.StatusBadRequest:
+		io.Copy(io.Discard, resp.Body)
+		s.failed.Add(uint64(len(batch)))
+		return nil, fmt.Errorf("elasticsearch bulk returned %s", resp.Status)
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		// Respons tidak terbaca: tidak diketahui record mana yang tersimpan. Tidak
+		// dicoba ulang (bisa menggandakan log) dan tidak dihitung terkirim.
+		s.failed.Add(uint64(len(batch)))
+		return nil, fmt.Errorf("decode elasticsearch bulk response: %w", err)
+	}
+	if !result.Errors {
+		s.sent.Add(uint64(len(batch)))
+		return nil, nil
+	}
+
+	var retry [][]byte
+	var itemErr error
+	if missing := len(batch) - len(result.Items); missing > 0 {
+		// Record tanpa hasil di items tidak diketahui nasibnya
+		s.failed.Add(uint64(missing))
+		itemErr = fmt.Errorf("elasticsearch bulk response has %d item(s) for %d record(s)", len(result.Items), len(batch))
+	}
+	for i, item := range result.Items {
+		if i >= len(batch) {
+			break
+		}
+		for _, outcome := range item {
+			switch {
+			case outcome.Status < http.StatusMultipleChoices:
+				s.sent.Add(1)
+			case outcome.Status == http.StatusTooManyRequests:
+				retry = append(retry, batch[i])
+			default:
+				s.failed.Add(1)
+				if outcome.Error != nil {
+					itemErr = fmt.Errorf("elasticsearch rejected log record: %s: %s", outcome.Error.Type, outcome.Error.Reason)
+				}
+			}
+		}
+	}
+	if itemErr == nil && len(retry) > 0 {
+		itemErr = fmt.Errorf("elasticsearch rejected %d log record(s) with 429", len(retry))
+	}
+	return retry, itemErr
+}
+
+// index mengganti layout tanggal di dalam {} dengan tanggal t (UTC)
+func (s *ElasticsearchSink) index(t time.Time) string {
+	start := strings.IndexByte(s.opts.Index, '{')
+	end := strings.IndexByte(s.opts.Index, '}')
+	if start < 0 || end < start {
+		return s.opts.Index
+	}
+	return s.opts.Index[:start] + t.UTC().Format(s.opts.Index[start+1:end]) + s.opts.Index[end+1:]
+}
+
+func jsonString(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
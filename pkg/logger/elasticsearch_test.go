@@ -0,0 +1,243 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bulkServer adalah Elasticsearch palsu; respond menentukan respons untuk setiap
+// bulk request (attempt dimulai dari 1) berdasarkan record yang diterima
+type bulkServer struct {
+	mu       sync.Mutex
+	requests [][]string
+	respond  func(attempt int, records []string, w http.ResponseWriter)
+}
+
+func (b *bulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var records []string
+	scanner := bufio.NewScanner(r.Body)
+	for line := 0; scanner.Scan(); line++ {
+		// Baris genap adalah action {"create":...}, baris ganjil adalah record
+		if line%2 == 1 {
+			records = append(records, scanner.Text())
+		}
+	}
+
+	b.mu.Lock()
+	b.requests = append(b.requests, records)
+	attempt := len(b.requests)
+	b.mu.Unlock()
+
+	b.respond(attempt, records, w)
+}
+
+func (b *bulkServer) Requests() [][]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([][]string(nil), b.requests...)
+}
+
+// bulkItems menulis respons bulk dengan satu item per status
+func bulkItems(w http.ResponseWriter, statuses ...int) {
+	items := make([]string, len(statuses))
+	hasErrors := false
+	for i, status := range statuses {
+		if status >= http.StatusMultipleChoices {
+			hasErrors = true
+			items[i] = fmt.Sprintf(`{"create":{"status":%d,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}}`, status)
+			continue
+		}
+		items[i] = fmt.Sprintf(`{"create":{"status":%d}}`, status)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"errors":%t,"items":[%s]}`, hasErrors, strings.Join(items, ","))
+}
+
+func created(n int) []int {
+	statuses := make([]int, n)
+	for i := range statuses {
+		statuses[i] = http.StatusCreated
+	}
+	return statuses
+}
+
+// newTestSink membuat sink yang hanya mengirim saat batch penuh atau Close
+func newTestSink(t *testing.T, server *bulkServer, opts ElasticsearchOptions) *ElasticsearchSink {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	opts.URL = ts.URL
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = time.Hour
+	}
+	opts.RetryBackoff = time.Millisecond
+	return NewElasticsearchSink(opts)
+}
+
+func writeRecords(sink *ElasticsearchSink, n int) {
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(sink, `{"msg":"record-%d"}`+"\n", i)
+	}
+}
+
+func closeSink(t *testing.T, sink *ElasticsearchSink) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sink.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestElasticsearchSinkRetriesRejectedItems(t *testing.T) {
+	server := &bulkServer{respond: func(attempt int, records []string, w http.ResponseWriter) {
+		if attempt == 1 {
+			// Item kedua ditolak karena kelebihan beban, item ketiga ditolak permanen
+			bulkItems(w, http.StatusCreated, http.StatusTooManyRequests, http.StatusBadRequest)
+			return
+		}
+		bulkItems(w, created(len(records))...)
+	}}
+	sink := newTestSink(t, server, ElasticsearchOptions{MaxRetries: 3})
+
+	writeRecords(sink, 3)
+	closeSink(t, sink)
+
+	requests := server.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("bulk requests = %d, want 2", len(requests))
+	}
+	if len(requests[1]) != 1 || requests[1][0] != `{"msg":"record-1"}` {
+		t.Errorf("retried records = %v, want only record-1", requests[1])
+	}
+
+	stats := sink.Stats()
+	if stats.Sent != 2 || stats.Failed != 1 || stats.Retries != 1 {
+		t.Errorf("stats = sent %d failed %d retries %d, want sent 2 failed 1 retries 1", stats.Sent, stats.Failed, stats.Retries)
+	}
+	if !strings.Contains(stats.LastError, "es_rejected_execution_exception") {
+		t.Errorf("LastError = %q, want the rejected item's error", stats.LastError)
+	}
+}
+
+func TestElasticsearchSinkRetriesServerErrors(t *testing.T) {
+	server := &bulkServer{respond: func(attempt int, records []string, w http.ResponseWriter) {
+		if attempt <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		bulkItems(w, created(len(records))...)
+	}}
+	sink := newTestSink(t, server, ElasticsearchOptions{MaxRetries: 3})
+
+	writeRecords(sink, 4)
+	closeSink(t, sink)
+
+	if requests := server.Requests(); len(requests) != 3 {
+		t.Fatalf("bulk requests = %d, want 3", len(requests))
+	}
+	stats := sink.Stats()
+	if stats.Sent != 4 || stats.Failed != 0 || stats.Retries != 2 {
+		t.Errorf("stats = sent %d failed %d retries %d, want sent 4 failed 0 retries 2", stats.Sent, stats.Failed, stats.Retries)
+	}
+}
+
+func TestElasticsearchSinkFailsAfterMaxRetries(t *testing.T) {
+	server := &bulkServer{respond: func(_ int, _ []string, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadGateway)
+	}}
+	sink := newTestSink(t, server, ElasticsearchOptions{MaxRetries: 2})
+
+	writeRecords(sink, 3)
+	closeSink(t, sink)
+
+	if requests := server.Requests(); len(requests) != 3 {
+		t.Fatalf("bulk requests = %d, want 3 (1 + 2 retries)", len(requests))
+	}
+	stats := sink.Stats()
+	if stats.Sent != 0 || stats.Failed != 3 || stats.Retries != 2 {
+		t.Errorf("stats = sent %d failed %d retries %d, want sent 0 failed 3 retries 2", stats.Sent, stats.Failed, stats.Retries)
+	}
+	if !strings.Contains(stats.LastError, "502") {
+		t.Errorf("LastError = %q, want the 502 status", stats.LastError)
+	}
+}
+
+func TestElasticsearchSinkUndecodableResponse(t *testing.T) {
+	server := &bulkServer{respond: func(_ int, _ []string, w http.ResponseWriter) {
+		w.Write([]byte("<html>proxy error</html>"))
+	}}
+	sink := newTestSink(t, server, ElasticsearchOptions{MaxRetries: 3})
+
+	writeRecords(sink, 2)
+	closeSink(t, sink)
+
+	if requests := server.Requests(); len(requests) != 1 {
+		t.Errorf("bulk requests = %d, want 1 (no retry)", len(requests))
+	}
+	stats := sink.Stats()
+	if stats.Sent != 0 || stats.Failed != 2 {
+		t.Errorf("stats = sent %d failed %d, want sent 0 failed 2", stats.Sent, stats.Failed)
+	}
+}
+
+func TestElasticsearchSinkCloseDrainsBuffer(t *testing.T) {
+	server := &bulkServer{respond: func(_ int, records []string, w http.ResponseWriter) {
+		bulkItems(w, created(len(records))...)
+	}}
+	sink := newTestSink(t, server, ElasticsearchOptions{BatchSize: 100})
+
+	writeRecords(sink, 5)
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Fatalf("bulk requests before Close = %d, want 0", len(requests))
+	}
+	closeSink(t, sink)
+
+	requests := server.Requests()
+	if len(requests) != 1 || len(requests[0]) != 5 {
+		t.Fatalf("bulk requests after Close = %v, want one request with 5 records", requests)
+	}
+	stats := sink.Stats()
+	if stats.Sent != 5 || stats.Queued != 0 {
+		t.Errorf("stats = sent %d queued %d, want sent 5 queued 0", stats.Sent, stats.Queued)
+	}
+
+	// Record setelah Close dibuang, bukan dikirim
+	writeRecords(sink, 1)
+	if stats := sink.Stats(); stats.Dropped != 1 {
+		t.Errorf("Dropped after Close = %d, want 1", stats.Dropped)
+	}
+}
+
+func TestElasticsearchSinkDropsWhenBufferFull(t *testing.T) {
+	arrived := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := &bulkServer{respond: func(attempt int, records []string, w http.ResponseWriter) {
+		if attempt == 1 {
+			arrived <- struct{}{}
+			<-release
+		}
+		bulkItems(w, created(len(records))...)
+	}}
+	sink := newTestSink(t, server, ElasticsearchOptions{BatchSize: 1, BufferSize: 1})
+
+	// Record pertama ditahan worker di bulk request, record kedua mengisi buffer
+	writeRecords(sink, 1)
+	<-arrived
+	writeRecords(sink, 3)
+	close(release)
+	closeSink(t, sink)
+
+	stats := sink.Stats()
+	if stats.Sent != 2 || stats.Dropped != 2 {
+		t.Errorf("stats = sent %d dropped %d, want sent 2 dropped 2", stats.Sent, stats.Dropped)
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger meneruskan log gorm ke slog (komponen "gorm").
+// Query yang gagal dicatat sebagai error, query yang lebih lama dari slowThreshold
+// sebagai warn, dan query lainnya sebagai debug.
+type GormLogger struct {
+	log           *slog.Logger
+	slowThreshold time.Duration
+}
+
+// NewGormLogger membuat logger gorm; slowThreshold 0 menonaktifkan log slow query
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{log: New("gorm"), slowThreshold: slowThreshold}
+}
+
+// LogMode diabaikan: level diatur lewat logging.levels.gorm
+func (l *GormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.log.InfoContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.log.WarnContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.log.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+// Trace mencatat setiap query beserta durasi dan jumlah baris
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+
+	var level slog.Level
+	var msg string
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = slog.LevelError, "query failed"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		level, msg = slog.LevelWarn, "slow query"
+	default:
+		level, msg = slog.LevelDebug, "query"
+	}
+	if !l.log.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []any{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	l.log.Log(ctx, level, msg, attrs...)
+}
+
+// ParamsFilter membuang nilai parameter dari SQL yang dicatat supaya data sensitif
+// (hash password, token) tidak masuk ke log
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, _ ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
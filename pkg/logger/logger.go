@@ -0,0 +1,264 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Options mengatur format dan level logger
+type Options struct {
+	// Level default untuk semua komponen: debug, info, warn, error
+	Level string
+	// Format: json (default) atau text
+	Format string
+	// Levels berisi level per komponen (contoh: {"gorm": "warn", "service": "debug"})
+	Levels map[string]string
+	// Output tujuan log; default os.Stdout
+	Output io.Writer
+	// Elasticsearch, jika diisi, juga mengirim semua log (JSON) ke Elasticsearch
+	Elasticsearch *ElasticsearchOptions
+}
+
+var (
+	// root adalah handler tujuan semua log; komponen memfilter level sendiri
+	root atomic.Pointer[slog.Handler]
+
+	mu           sync.Mutex
+	sink         *ElasticsearchSink
+	defaultLevel = new(slog.LevelVar)
+	configured   = map[string]slog.Level{}
+	levels       = map[string]*slog.LevelVar{}
+)
+
+func init() {
+	var handler slog.Handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})
+	root.Store(&handler)
+}
+
+// Setup memasang handler dan level sesuai opts, lalu menjadikan logger "app"
+// sebagai slog default (log.Printf dari package log ikut menjadi log terstruktur).
+// Boleh dipanggil ulang, misalnya saat config berubah.
+func Setup(opts Options) error {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	componentLevels := make(map[string]slog.Level, len(opts.Levels))
+	for component, value := range opts.Levels {
+		componentLevel, err := ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("logging.levels.%s: %w", component, err)
+		}
+		componentLevels[strings.ToLower(component)] = componentLevel
+	}
+
+	output := opts.Output
+	if output == nil {
+		output = os.Stdout
+	}
+	handlerOptions := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(output, handlerOptions)
+	case "text":
+		handler = slog.NewTextHandler(output, handlerOptions)
+	default:
+		return fmt.Errorf("unsupported log format %q", opts.Format)
+	}
+
+	var newSink *ElasticsearchSink
+	if opts.Elasticsearch != nil {
+		newSink = NewElasticsearchSink(*opts.Elasticsearch)
+		handler = fanoutHandler{handler, slog.NewJSONHandler(newSink, handlerOptions)}
+	}
+
+	mu.Lock()
+	defaultLevel.Set(level)
+	configured = componentLevels
+	for component, levelVar := range levels {
+		levelVar.Set(levelOf(component))
+	}
+	oldSink := sink
+	sink = newSink
+	mu.Unlock()
+
+	root.Store(&handler)
+	slog.SetDefault(New("app"))
+
+	// Sink lama (jika Setup dipanggil ulang) dikosongkan di background
+	if oldSink != nil {
+		go oldSink.Close(context.Background())
+	}
+	return nil
+}
+
+// Close mengirim sisa log yang masih di buffer sink (misalnya Elasticsearch)
+// sebelum aplikasi berhenti, maksimal sampai ctx berakhir
+func Close(ctx context.Context) error {
+	mu.Lock()
+	current := sink
+	mu.Unlock()
+	if current == nil {
+		return nil
+	}
+	return current.Close(ctx)
+}
+
+// Stats mengembalikan counter sink log yang aktif (kosong jika tidak ada)
+func Stats() []SinkStats {
+	mu.Lock()
+	current := sink
+	mu.Unlock()
+	if current == nil {
+		return []SinkStats{}
+	}
+	return []SinkStats{current.Stats()}
+}
+
+// fanoutHandler meneruskan setiap record ke beberapa handler
+type fanoutHandler []slog.Handler
+
+func (h fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h fanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range h {
+		if handler.Enabled(ctx, record.Level) {
+			errs = append(errs, handler.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanoutHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (h fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make(fanoutHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return handlers
+}
+
+// ParseLevel mengubah nama level (debug, info, warn, error) menjadi slog.Level
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("invalid log level %q", value)
+	}
+	return level, nil
+}
+
+// New mengembalikan logger untuk komponen (misalnya "handler", "service", "gorm").
+// Setiap record diberi atribut component, request_id dari ctx (lihat WithRequestID)
+// serta trace_id dan span_id jika ctx membawa span OpenTelemetry.
+// Aman dipakai sebagai variabel package karena level dan handler dibaca saat log ditulis.
+func New(component string) *slog.Logger {
+	component = strings.ToLower(component)
+
+	mu.Lock()
+	levelVar, ok := levels[component]
+	if !ok {
+		levelVar = new(slog.LevelVar)
+		levelVar.Set(levelOf(component))
+		levels[component] = levelVar
+	}
+	mu.Unlock()
+
+	return slog.New(&componentHandler{level: levelVar}).With("component", component)
+}
+
+// levelOf mengembalikan level yang dikonfigurasi untuk komponen; mu harus dipegang
+func levelOf(component string) slog.Level {
+	if level, ok := configured[component]; ok {
+		return level
+	}
+	return defaultLevel.Level()
+}
+
+// componentHandler memfilter level per komponen lalu meneruskan record ke root handler.
+// Atribut dan group dari With/WithGroup disimpan dan diterapkan ke root handler terbaru.
+type componentHandler struct {
+	level *slog.LevelVar
+	ops   []func(slog.Handler) slog.Handler
+}
+
+func (h *componentHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *componentHandler) Handle(ctx context.Context, record slog.Record) error {
+	handler := *root.Load()
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if ctx != nil {
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			record.AddAttrs(
+				slog.String("trace_id", spanContext.TraceID().String()),
+				slog.String("span_id", spanContext.SpanID().String()),
+			)
+		}
+	}
+	return handler.Handle(ctx, record)
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *componentHandler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &componentHandler{level: h.level, ops: append(ops, op)}
+}
+
+// requestIDKey - Key context untuk request ID
+type requestIDKey struct{}
+
+// WithRequestID menyimpan request ID di ctx; semua log dengan ctx ini mencantumkannya
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID mengembalikan request ID dari ctx ("" jika tidak ada)
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// namespace adalah prefix semua metric aplikasi
+const namespace = "app"
+
+// Metrics menyimpan semua metric Prometheus aplikasi.
+// Semua method aman dipanggil pada *Metrics nil (tidak mencatat apa pun),
+// sehingga komponen tetap bisa dipakai tanpa metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests      *prometheus.CounterVec
+	httpDuration      *prometheus.HistogramVec
+	logins            *prometheus.CounterVec
+	tokenFailures     *prometheus.CounterVec
+	rateLimited       *prometheus.CounterVec
+	migrationDuration *prometheus.HistogramVec
+	seedDuration      *prometheus.HistogramVec
+}
+
+// New membuat dan mendaftarkan semua metric ke registry.
+// Gunakan prometheus.NewRegistry() di test agar setiap test punya registry sendiri.
+func New(registry *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		registry: registry,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_logins_total",
+			Help:      "Login attempts by result (success, failure) and failure reason.",
+		}, []string{"result", "reason"}),
+		tokenFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_token_validation_failures_total",
+			Help:      "Rejected bearer tokens by reason.",
+		}, []string{"reason"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_requests_total",
+			Help:      "Requests rejected by the rate limiter by route group and principal type.",
+		}, []string{"group", "principal"}),
+		migrationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "migration_duration_seconds",
+			Help:      "Duration of each schema migration by direction, version and result.",
+			Buckets:   []float64{.01, .05, .1, .5, 1, 5, 30, 120},
+		}, []string{"direction", "version", "result"}),
+		seedDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "seeder_duration_seconds",
+			Help:      "Duration of seeding runs by environment and result.",
+			Buckets:   []float64{.01, .05, .1, .5, 1, 5, 30, 120},
+		}, []string{"env", "result"}),
+	}
+
+	registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.logins,
+		m.tokenFailures,
+		m.rateLimited,
+		m.migrationDuration,
+		m.seedDuration,
+	)
+	return m
+}
+
+// NewDefault membuat registry baru berisi metric runtime Go dan proses, lalu metric aplikasi
+func NewDefault() *Metrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return New(registry)
+}
+
+// Registry mengembalikan registry tempat metric didaftarkan
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler mengembalikan handler HTTP untuk endpoint /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Push mengirim semua metric di registry ke Prometheus Pushgateway di url dengan
+// label job, menggantikan hasil push sebelumnya untuk job yang sama. Dipakai
+// perintah CLI berumur pendek (contoh seed) yang tidak pernah di-scrape lewat /metrics.
+func (m *Metrics) Push(ctx context.Context, url, job string) error {
+	if m == nil {
+		return nil
+	}
+	return push.New(url, job).Gatherer(m.registry).PushContext(ctx)
+}
+
+// RegisterDB mendaftarkan statistik connection pool (open, in use, idle, wait, ...)
+// dengan label db_name, misalnya "primary" atau "replica-1"
+func (m *Metrics) RegisterDB(name string, db *sql.DB) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveHTTPRequest mencatat satu request HTTP; route adalah template route (contoh /api/users/:id)
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	statusLabel := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	m.httpDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// LoginSucceeded mencatat login yang berhasil
+func (m *Metrics) LoginSucceeded() {
+	if m == nil {
+		return
+	}
+	m.logins.WithLabelValues("success", "").Inc()
+}
+
+// LoginFailed mencatat login yang gagal, contoh reason: invalid_request, invalid_credentials, disabled
+func (m *Metrics) LoginFailed(reason string) {
+	if m == nil {
+		return
+	}
+	m.logins.WithLabelValues("failure", reason).Inc()
+}
+
+// TokenValidationFailed mencatat token yang ditolak, contoh reason: missing, expired, invalid_signature
+func (m *Metrics) TokenValidationFailed(reason string) {
+	if m == nil {
+		return
+	}
+	m.tokenFailures.WithLabelValues(reason).Inc()
+}
+
+// RateLimited mencatat request yang ditolak rate limiter; principal adalah jenisnya (user, ip)
+func (m *Metrics) RateLimited(group, principal string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(group, principal).Inc()
+}
+
+// ObserveMigration mencatat durasi satu migrasi (memenuhi database.MigrationObserver)
+func (m *Metrics) ObserveMigration(direction string, version uint, name string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.migrationDuration.WithLabelValues(direction, strconv.FormatUint(uint64(version), 10), result(err)).Observe(duration.Seconds())
+}
+
+// ObserveSeed mencatat durasi satu kali seeding (memenuhi seeder.Observer)
+func (m *Metrics) ObserveSeed(env string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.seedDuration.WithLabelValues(env, result(err)).Observe(duration.Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
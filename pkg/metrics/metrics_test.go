@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// counterValue mengembalikan nilai counter name dengan label persis labels, atau 0
+func counterValue(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			if len(metric.GetLabel()) != len(labels) {
+				continue
+			}
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] != label.GetValue() {
+					continue metrics
+				}
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestCounters(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := New(registry)
+
+	m.ObserveHTTPRequest("GET", "/api/users/:id", 200, 10*time.Millisecond)
+	m.ObserveHTTPRequest("GET", "/api/users/:id", 200, 20*time.Millisecond)
+	m.ObserveHTTPRequest("POST", "/api/login", 401, time.Millisecond)
+	m.LoginSucceeded()
+	m.LoginFailed("invalid_password")
+	m.LoginFailed("invalid_password")
+	m.TokenValidationFailed("expired")
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   float64
+	}{
+		{"app_http_requests_total", map[string]string{"method": "GET", "route": "/api/users/:id", "status": "200"}, 2},
+		{"app_http_requests_total", map[string]string{"method": "POST", "route": "/api/login", "status": "401"}, 1},
+		{"app_auth_logins_total", map[string]string{"result": "success", "reason": ""}, 1},
+		{"app_auth_logins_total", map[string]string{"result": "failure", "reason": "invalid_password"}, 2},
+		{"app_auth_token_validation_failures_total", map[string]string{"reason": "expired"}, 1},
+		{"app_auth_token_validation_failures_total", map[string]string{"reason": "missing"}, 0},
+	}
+	for _, tt := range tests {
+		if got := counterValue(t, registry, tt.name, tt.labels); got != tt.want {
+			t.Errorf("%s%v = %v, want %v", tt.name, tt.labels, got, tt.want)
+		}
+	}
+
+	// Registry terpisah: metric dari test lain tidak ikut terhitung
+	if got := counterValue(t, prometheus.NewRegistry(), "app_auth_logins_total", map[string]string{"result": "success", "reason": ""}); got != 0 {
+		t.Errorf("fresh registry login count = %v, want 0", got)
+	}
+}
+
+func TestHandlerExposesMetrics(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	m.TokenValidationFailed("missing")
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(recorder.Body.String(), `app_auth_token_validation_failures_total{reason="missing"} 1`) {
+		t.Errorf("/metrics output does not contain the token failure counter:\n%s", recorder.Body.String())
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	m.ObserveHTTPRequest("GET", "/", 200, time.Millisecond)
+	m.LoginSucceeded()
+	m.LoginFailed("disabled")
+	m.TokenValidationFailed("missing")
+	m.RateLimited("login", "ip")
+	m.ObserveSeed("development", time.Second, nil)
+	if err := m.Push(context.Background(), "http://127.0.0.1:0", "seeder"); err != nil {
+		t.Errorf("Push on nil Metrics = %v, want nil", err)
+	}
+}
+
+func TestPush(t *testing.T) {
+	var method, path, body string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(content)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	m := New(prometheus.NewRegistry())
+	m.ObserveSeed("development", 2*time.Second, errors.New("fixture failed"))
+	if err := m.Push(context.Background(), gateway.URL, "seeder"); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/seeder" {
+		t.Errorf("push request = %s %s, want PUT /metrics/job/seeder", method, path)
+	}
+	// Body memakai format protobuf; nama metric tetap tertulis apa adanya
+	if !strings.Contains(body, "app_seeder_duration_seconds") {
+		t.Error("pushed body does not contain app_seeder_duration_seconds")
+	}
+}
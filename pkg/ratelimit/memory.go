@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// MemoryStore menyimpan bucket di memori proses. Bucket yang sudah penuh kembali
+// dibuang secara berkala karena state-nya sama dengan bucket baru.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	now     func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type memoryBucket struct {
+	bucket
+	limit Limit
+}
+
+// NewMemoryStore membuat store dan menjalankan pembersihan setiap cleanupInterval.
+// Panggil Close untuk menghentikan pembersihan.
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	if cleanupInterval <= 0 {
+		cleanupInterval = time.Minute
+	}
+	s := &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.cleanup(cleanupInterval)
+	return s
+}
+
+// Take mengambil satu token dari bucket key
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{limit: limit}
+		s.buckets[key] = b
+	} else if b.limit != limit {
+		// Limit berubah (misalnya config diganti): token yang tersisa dibawa,
+		// dibatasi kapasitas baru, agar reload tidak mengisi ulang semua bucket
+		b.tokens = math.Min(b.tokens, limit.capacity())
+		b.limit = limit
+	}
+	return b.take(s.now(), limit), nil
+}
+
+// Len mengembalikan jumlah bucket yang sedang disimpan
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+// Close menghentikan pembersihan berkala
+func (s *MemoryStore) Close() {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+}
+
+func (s *MemoryStore) cleanup(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			now := s.now()
+			for key, b := range s.buckets {
+				if b.full(now, b.limit) {
+					delete(s.buckets, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestStore membuat MemoryStore dengan jam yang bisa dimajukan lewat advance
+func newTestStore(t *testing.T) (store *MemoryStore, advance func(time.Duration)) {
+	t.Helper()
+	store = NewMemoryStore(time.Hour)
+	t.Cleanup(store.Close)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	return store, func(d time.Duration) { now = now.Add(d) }
+}
+
+// step adalah satu pemanggilan Take setelah jam dimajukan sebesar after
+type step struct {
+	after         time.Duration
+	key           string
+	limit         Limit
+	wantAllowed   bool
+	wantRemaining int
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	perMinute := Limit{Requests: 2, Period: time.Minute}
+	burst := Limit{Requests: 6, Period: time.Minute, Burst: 3}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "refill",
+			steps: []step{
+				{0, "a", perMinute, true, 1},
+				{0, "a", perMinute, true, 0},
+				{0, "a", perMinute, false, 0},
+				// Satu token diisi setiap 30 detik
+				{29 * time.Second, "a", perMinute, false, 0},
+				{time.Second, "a", perMinute, true, 0},
+				// Bucket tidak pernah melebihi kapasitas
+				{time.Hour, "a", perMinute, true, 1},
+			},
+		},
+		{
+			name: "burst",
+			steps: []step{
+				{0, "a", burst, true, 2},
+				{0, "a", burst, true, 1},
+				{0, "a", burst, true, 0},
+				{0, "a", burst, false, 0},
+				{10 * time.Second, "a", burst, true, 0},
+			},
+		},
+		{
+			name: "limit change keeps remaining tokens",
+			steps: []step{
+				{0, "a", burst, true, 2},
+				{0, "a", burst, true, 1},
+				// Kapasitas naik: sisa 1 token dibawa, bukan bucket penuh
+				{0, "a", Limit{Requests: 10, Period: time.Minute}, true, 0},
+				{0, "a", Limit{Requests: 10, Period: time.Minute}, false, 0},
+			},
+		},
+		{
+			name: "limit change caps at new burst",
+			steps: []step{
+				{0, "a", Limit{Requests: 10, Period: time.Minute}, true, 9},
+				{0, "a", Limit{Requests: 10, Period: time.Minute, Burst: 2}, true, 1},
+				{0, "a", Limit{Requests: 10, Period: time.Minute, Burst: 2}, true, 0},
+				{0, "a", Limit{Requests: 10, Period: time.Minute, Burst: 2}, false, 0},
+			},
+		},
+		{
+			name: "keys are isolated",
+			steps: []step{
+				{0, "a", perMinute, true, 1},
+				{0, "a", perMinute, true, 0},
+				{0, "a", perMinute, false, 0},
+				{0, "b", perMinute, true, 1},
+				{0, "a", perMinute, false, 0},
+				{0, "b", perMinute, true, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, advance := newTestStore(t)
+			for i, s := range tt.steps {
+				advance(s.after)
+				result, err := store.Take(context.Background(), s.key, s.limit)
+				if err != nil {
+					t.Fatalf("step %d: Take: %v", i, err)
+				}
+				if result.Allowed != s.wantAllowed || result.Remaining != s.wantRemaining {
+					t.Errorf("step %d (%s): allowed %t remaining %d, want allowed %t remaining %d",
+						i, s.key, result.Allowed, result.Remaining, s.wantAllowed, s.wantRemaining)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRetryAfterAndReset(t *testing.T) {
+	store, _ := newTestStore(t)
+	limit := Limit{Requests: 2, Period: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		store.Take(context.Background(), "a", limit)
+	}
+	result, _ := store.Take(context.Background(), "a", limit)
+	if result.Allowed || result.RetryAfter != 30*time.Second || result.Reset != time.Minute || result.Limit != 2 {
+		t.Errorf("result = %+v, want denied with RetryAfter 30s, Reset 1m and Limit 2", result)
+	}
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit adalah aturan token bucket: Requests token diisi ulang merata setiap
+// Period, dengan kapasitas Burst (default Requests)
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// capacity mengembalikan kapasitas bucket
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate mengembalikan jumlah token yang diisi ulang per detik
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Valid melaporkan apakah limit bisa dipakai (Requests dan Period positif)
+func (l Limit) Valid() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// Result adalah hasil pengambilan satu token
+type Result struct {
+	Allowed bool
+	// Limit adalah kapasitas bucket (untuk header RateLimit-Limit)
+	Limit int
+	// Remaining adalah sisa token setelah request ini
+	Remaining int
+	// Reset adalah waktu sampai bucket penuh kembali
+	Reset time.Duration
+	// RetryAfter adalah waktu sampai satu token tersedia (hanya jika ditolak)
+	RetryAfter time.Duration
+}
+
+// Store menyimpan state bucket per key. MemoryStore cukup untuk satu instance;
+// untuk beberapa instance, implementasikan Store di atas penyimpanan bersama (misalnya Redis).
+type Store interface {
+	// Take mengambil satu token dari bucket key sesuai limit
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// bucket adalah state token bucket
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take mengisi ulang bucket sesuai waktu yang berlalu lalu mengambil satu token
+func (b *bucket) take(now time.Time, limit Limit) Result {
+	capacity := limit.capacity()
+	rate := limit.rate()
+
+	if b.last.IsZero() {
+		b.tokens = capacity
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	}
+	b.last = now
+
+	result := Result{Limit: int(capacity)}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = seconds((capacity - b.tokens) / rate)
+	return result
+}
+
+// full melaporkan apakah bucket sudah penuh kembali pada waktu now
+func (b *bucket) full(now time.Time, limit Limit) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*limit.rate() >= limit.capacity()
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
@@ -0,0 +1,153 @@
+package seeder
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed fixtures/*.yaml
+var defaultFixtures embed.FS
+
+// Fixture adalah satu set seed yang dideklarasikan dalam file YAML/JSON
+type Fixture struct {
+	// Name harus unik; dipakai oleh DependsOn fixture lain
+	Name string `yaml:"name" json:"name"`
+	// DependsOn berisi nama fixture yang harus dijalankan lebih dulu
+	DependsOn []string `yaml:"depends_on" json:"depends_on"`
+	// Environments membatasi fixture ke environment tertentu; kosong berarti semua environment
+	Environments []string      `yaml:"environments" json:"environments"`
+	Permissions  []string      `yaml:"permissions" json:"permissions"`
+	Roles        []RoleFixture `yaml:"roles" json:"roles"`
+	Users        []UserFixture `yaml:"users" json:"users"`
+}
+
+// RoleFixture mendeklarasikan role beserta permission-nya (role-permission matrix)
+type RoleFixture struct {
+	Name        string   `yaml:"name" json:"name"`
+	Permissions []string `yaml:"permissions" json:"permissions"`
+}
+
+// UserFixture mendeklarasikan user. Password tidak pernah ditulis di fixture:
+// diambil dari env PasswordEnv, atau dibuat acak saat user pertama kali dibuat.
+type UserFixture struct {
+	Username    string   `yaml:"username" json:"username"`
+	Role        string   `yaml:"role" json:"role"`
+	Roles       []string `yaml:"roles" json:"roles"`
+	Permissions []string `yaml:"permissions" json:"permissions"`
+	PasswordEnv string   `yaml:"password_env" json:"password_env"`
+}
+
+// appliesTo memeriksa apakah fixture berlaku untuk env
+func (f Fixture) appliesTo(env string) bool {
+	if len(f.Environments) == 0 {
+		return true
+	}
+	for _, e := range f.Environments {
+		if strings.EqualFold(e, env) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultFixtures mengembalikan fixture bawaan yang di-embed di binary
+func DefaultFixtures() ([]Fixture, error) {
+	return LoadFixtures(defaultFixtures, "fixtures")
+}
+
+// LoadFixtures membaca semua file .yaml, .yml dan .json di dir
+func LoadFixtures(fsys fs.FS, dir string) ([]Fixture, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []Fixture
+	for _, entry := range entries {
+		ext := strings.ToLower(path.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		// YAML adalah superset JSON sehingga satu parser cukup untuk keduanya
+		var fixture Fixture
+		if err := yaml.Unmarshal(content, &fixture); err != nil {
+			return nil, fmt.Errorf("parse fixture %s: %w", entry.Name(), err)
+		}
+		if fixture.Name == "" {
+			fixture.Name = strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+// orderFixtures memilih fixture untuk env lalu mengurutkannya sesuai DependsOn.
+// Dependency yang tidak berlaku untuk env dianggap error agar tidak diam-diam terlewat.
+func orderFixtures(fixtures []Fixture, env string) ([]Fixture, error) {
+	byName := make(map[string]Fixture)
+	var names []string
+	for _, fixture := range fixtures {
+		if !fixture.appliesTo(env) {
+			continue
+		}
+		if _, exists := byName[fixture.Name]; exists {
+			return nil, fmt.Errorf("duplicate fixture %q", fixture.Name)
+		}
+		byName[fixture.Name] = fixture
+		names = append(names, fixture.Name)
+	}
+	// Urutan stabil untuk fixture yang tidak saling bergantung
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var ordered []Fixture
+
+	var visit func(name string, from string) error
+	visit = func(name string, from string) error {
+		fixture, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("fixture %q depends on %q which is not available in environment %q", from, name, env)
+		}
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("fixture dependency cycle at %q", name)
+		case done:
+			return nil
+		}
+
+		state[name] = visiting
+		for _, dependency := range fixture.DependsOn {
+			if err := visit(dependency, name); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		ordered = append(ordered, fixture)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, name); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
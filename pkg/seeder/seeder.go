@@ -1,355 +1,255 @@
 package seeder
 
 import (
-	"log"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"project/internal/models"
+	"project/pkg/logger"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-// SeedSuperAdmin akan menambahkan user super admin ke database
-func SeedSuperAdmin(db *gorm.DB, password string) error {
-	// Cek apakah role "superadmin" sudah ada atau buat jika belum ada
-	var superAdminRole models.Role
-	if err := db.Where("name = ?", "superadmin").First(&superAdminRole).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			superAdminRole = models.Role{Name: "superadmin"}
-			if err := db.Create(&superAdminRole).Error; err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
-	}
+// log adalah logger komponen "seeder"
+var log = logger.New("seeder")
 
-	// Daftar permissions yang dibutuhkan
-	permissionsList := []string{"create_user", "edit_user", "delete_user", "view_user"}
-	var permissions []models.Permission
+// allPermissions adalah wildcard untuk semua permission yang sudah di-seed
+const allPermissions = "*"
 
-	// Loop untuk memeriksa setiap permission, insert jika tidak ada
-	for _, permName := range permissionsList {
-		var permission models.Permission
-		if err := db.Where("name = ?", permName).First(&permission).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				// Buat permission baru jika belum ada
-				permission = models.Permission{Name: permName}
-				if err := db.Create(&permission).Error; err != nil {
-					return err
-				}
-			} else {
-				return err
-			}
-		}
-		permissions = append(permissions, permission)
-	}
+// generatedPassword dicatat untuk user baru yang password-nya dibuat acak
+type generatedPassword struct {
+	Username string
+	Password string
+}
 
-	// Kaitkan semua permissions dengan superAdminRole jika belum terkait
-	if err := db.Model(&superAdminRole).Association("Permissions").Replace(&permissions); err != nil {
-		return err
-	}
+// Seeder menerapkan fixture ke database dengan semantik upsert,
+// sehingga aman dijalankan berulang kali
+type Seeder struct {
+	db       *gorm.DB
+	fixtures []Fixture
+	out      io.Writer
+	observer Observer
+}
 
-	// Cek apakah user "superadmin" sudah ada atau buat jika belum ada
-	var superAdminUser models.User
-	if err := db.Where("username = ?", "superadmin@mail.com").First(&superAdminUser).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			// Hash password superadmin
-			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-			if err != nil {
-				return err
-			}
+// Observer menerima durasi dan hasil setiap kali Run dijalankan (misalnya untuk metrics)
+type Observer interface {
+	ObserveSeed(env string, duration time.Duration, err error)
+}
 
-			// Buat user superadmin baru
-			superAdminUser = models.User{
-				Username: "superadmin@mail.com",
-				Password: string(hashedPassword),
-				Role:     "superadmin",
-			}
+// SetObserver memasang observer untuk Run berikutnya
+func (s *Seeder) SetObserver(observer Observer) {
+	s.observer = observer
+}
 
-			if err := db.Create(&superAdminUser).Error; err != nil {
-				return err
-			}
+// New membuat Seeder; password yang dibuat acak dicetak ke out
+func New(db *gorm.DB, fixtures []Fixture, out io.Writer) *Seeder {
+	return &Seeder{db: db, fixtures: fixtures, out: out}
+}
 
-			// Kaitkan permissions dengan user superadmin
-			if err := db.Model(&superAdminUser).Association("Permissions").Replace(&permissions); err != nil {
-				return err
-			}
+// Run menjalankan semua fixture yang berlaku untuk env, sesuai urutan dependency,
+// dalam satu transaksi
+func (s *Seeder) Run(ctx context.Context, env string) (err error) {
+	if s.observer != nil {
+		start := time.Now()
+		defer func() { s.observer.ObserveSeed(env, time.Since(start), err) }()
+	}
+
+	fixtures, err := orderFixtures(s.fixtures, env)
+	if err != nil {
+		return err
+	}
 
-			// Kaitkan role superadmin dengan user superadmin
-			if err := db.Model(&superAdminUser).Association("Roles").Replace(&superAdminRole); err != nil {
-				return err
+	var generated []generatedPassword
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		generated = nil
+		for _, fixture := range fixtures {
+			log.InfoContext(ctx, "seeding fixture", "fixture", fixture.Name, "env", env)
+			passwords, err := seedFixture(tx, fixture)
+			if err != nil {
+				return fmt.Errorf("seed %s: %w", fixture.Name, err)
 			}
-		} else {
-			return err
+			generated = append(generated, passwords...)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Dicetak setelah commit supaya password yang ditampilkan pasti tersimpan
+	for _, password := range generated {
+		fmt.Fprintf(s.out, "Generated password for %s: %s (shown only once, store it now)\n", password.Username, password.Password)
 	}
 
-	log.Println("Super Admin dan data terkait berhasil dibuat atau sudah ada.")
+	log.InfoContext(ctx, "seeding completed", "env", env, "fixtures", len(fixtures))
 	return nil
 }
 
-// package seeder
-
-// import (
-// 	"log"
-// 	"project/internal/models"
-
-// 	"golang.org/x/crypto/bcrypt"
-// 	"gorm.io/gorm"
-// )
-
-// // SeedSuperAdmin akan menambahkan user super admin ke database
-// func SeedSuperAdmin(db *gorm.DB, password string) error {
-// 	// Cek apakah role superadmin sudah ada
-// 	var superAdminRole models.Role
-// 	if err := db.Where("name = ?", "superadmin").First(&superAdminRole).Error; err != nil {
-// 		if err == gorm.ErrRecordNotFound {
-// 			superAdminRole = models.Role{Name: "superadmin"}
-// 			if err := db.Create(&superAdminRole).Error; err != nil {
-// 				return err
-// 			}
-// 		} else {
-// 			return err
-// 		}
-// 	}
-
-// 	// Cek apakah role admin sudah ada
-// 	var adminRole models.Role
-// 	if err := db.Where("name = ?", "admin").First(&adminRole).Error; err != nil {
-// 		if err == gorm.ErrRecordNotFound {
-// 			adminRole = models.Role{Name: "admin"}
-// 			if err := db.Create(&adminRole).Error; err != nil {
-// 				return err
-// 			}
-// 		} else {
-// 			return err
-// 		}
-// 	}
-
-// 	// Tambahkan permissions jika belum ada
-// 	permissions := []models.Permission{
-// 		{Name: "create_user"},
-// 		{Name: "edit_user"},
-// 		{Name: "delete_user"},
-// 		{Name: "view_user"},
-// 	}
-
-// 	// Pastikan permissions ada di database
-// 	for _, permission := range permissions {
-// 		var perm models.Permission
-// 		if err := db.Where("name = ?", permission.Name).First(&perm).Error; err != nil {
-// 			if err == gorm.ErrRecordNotFound {
-// 				if err := db.Create(&permission).Error; err != nil {
-// 					return err
-// 				}
-// 			} else {
-// 				return err
-// 			}
-// 		}
-// 	}
-
-// 	// Kaitkan semua permissions dengan superAdminRole jika belum terkait
-// 	if err := db.Model(&superAdminRole).Association("Permissions").Clear(); err != nil {
-// 		return err
-// 	}
-// 	if err := db.Model(&superAdminRole).Association("Permissions").Append(&permissions); err != nil {
-// 		return err
-// 	}
-
-// 	// Cek apakah user superadmin sudah ada
-// 	var user models.User
-// 	if err := db.Where("username = ?", "superadmin@mail.com").First(&user).Error; err != nil {
-// 		if err == gorm.ErrRecordNotFound {
-// 			// Hash password
-// 			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-// 			if err != nil {
-// 				return err
-// 			}
-
-// 			// Buat user superadmin baru
-// 			superAdmin := models.User{
-// 				Username:    "superadmin@mail.com",
-// 				Password:    string(hashedPassword),
-// 				Role:        "superadmin",
-// 				Roles:       []models.Role{superAdminRole},
-// 				Permissions: permissions,
-// 			}
-
-// 			if err := db.Create(&superAdmin).Error; err != nil {
-// 				return err
-// 			}
-// 		} else {
-// 			return err
-// 		}
-// 	}
-
-// 	log.Println("Super Admin dan data terkait berhasil dibuat atau sudah ada.")
-// 	return nil
-// }
-
-// package seeder
-
-// import (
-// 	"log"
-// 	"project/internal/models"
-
-// 	"golang.org/x/crypto/bcrypt"
-// 	"gorm.io/gorm"
-// )
-
-// // SeedSuperAdmin akan menambahkan user super admin ke database
-// func SeedSuperAdmin(db *gorm.DB, password string) error {
-// 	// Cek apakah sudah ada role superadmin
-// 	var superAdminRole models.Role
-// 	if err := db.Where("name = ?", "superadmin").First(&superAdminRole).Error; err != nil {
-// 		if err == gorm.ErrRecordNotFound {
-// 			superAdminRole = models.Role{Name: "superadmin"}
-// 			if err := db.Create(&superAdminRole).Error; err != nil {
-// 				return err
-// 			}
-// 		} else {
-// 			return err
-// 		}
-// 	}
-
-// 	// Cek apakah sudah ada role admin
-// 	var adminRole models.Role
-// 	if err := db.Where("name = ?", "admin").First(&adminRole).Error; err != nil {
-// 		if err == gorm.ErrRecordNotFound {
-// 			adminRole = models.Role{Name: "admin"}
-// 			if err := db.Create(&adminRole).Error; err != nil {
-// 				return err
-// 			}
-// 		} else {
-// 			return err
-// 		}
-// 	}
-
-// 	// Tambahkan permissions jika belum ada
-// 	permissions := []models.Permission{
-// 		{Name: "create_user"},
-// 		{Name: "edit_user"},
-// 		{Name: "delete_user"},
-// 		{Name: "view_user"},
-// 	}
-
-// 	for _, permission := range permissions {
-// 		var perm models.Permission
-// 		if err := db.Where("name = ?", permission.Name).First(&perm).Error; err != nil {
-// 			if err == gorm.ErrRecordNotFound {
-// 				if err := db.Create(&permission).Error; err != nil {
-// 					return err
-// 				}
-// 			} else {
-// 				return err
-// 			}
-// 		}
-// 	}
-
-// 	// Kaitkan semua permissions dengan superAdminRole jika belum terkait
-// 	if err := db.Model(&superAdminRole).Association("Permissions").Append(&permissions); err != nil {
-// 		return err
-// 	}
-
-// 	// Cek apakah user superadmin sudah ada
-// 	var user models.User
-// 	if err := db.Where("username = ?", "superadmin@mail.com").First(&user).Error; err != nil {
-// 		if err == gorm.ErrRecordNotFound {
-// 			// Hash password
-// 			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-// 			if err != nil {
-// 				return err
-// 			}
-
-// 			// Buat user superadmin baru
-// 			superAdmin := models.User{
-// 				Username:    "superadmin@mail.com",
-// 				Password:    string(hashedPassword),
-// 				Role:        "superadmin",
-// 				Roles:       []models.Role{superAdminRole},
-// 				Permissions: permissions,
-// 			}
+// seedFixture menerapkan permission, role lalu user dari satu fixture
+func seedFixture(tx *gorm.DB, fixture Fixture) ([]generatedPassword, error) {
+	for _, name := range fixture.Permissions {
+		permission := models.Permission{Name: name}
+		if err := tx.Where("name = ?", name).FirstOrCreate(&permission).Error; err != nil {
+			return nil, err
+		}
+	}
 
-// 			if err := db.Create(&superAdmin).Error; err != nil {
-// 				return err
-// 			}
-// 		} else {
-// 			return err
-// 		}
-// 	}
+	for _, roleFixture := range fixture.Roles {
+		if err := seedRole(tx, roleFixture); err != nil {
+			return nil, err
+		}
+	}
 
-// 	log.Println("Super Admin dan data terkait berhasil dibuat atau sudah ada.")
-// 	return nil
-// }
+	var generated []generatedPassword
+	for _, userFixture := range fixture.Users {
+		password, err := seedUser(tx, userFixture)
+		if err != nil {
+			return nil, err
+		}
+		if password != nil {
+			generated = append(generated, *password)
+		}
+	}
+	return generated, nil
+}
 
-// package seeder
+// seedRole membuat role jika belum ada dan menyamakan permission-nya dengan fixture
+func seedRole(tx *gorm.DB, roleFixture RoleFixture) error {
+	role := models.Role{Name: roleFixture.Name}
+	if err := tx.Where("name = ?", roleFixture.Name).FirstOrCreate(&role).Error; err != nil {
+		return err
+	}
 
-// import (
-// 	"log"
-// 	"project/internal/models"
+	permissions, err := findPermissions(tx, roleFixture.Permissions)
+	if err != nil {
+		return fmt.Errorf("role %s: %w", roleFixture.Name, err)
+	}
+	return tx.Model(&role).Association("Permissions").Replace(&permissions)
+}
 
-// 	"golang.org/x/crypto/bcrypt"
-// 	"gorm.io/gorm"
-// )
+// seedUser membuat user jika belum ada; user yang sudah ada hanya diperbarui
+// role dan relasinya, password-nya tidak pernah ditimpa
+func seedUser(tx *gorm.DB, userFixture UserFixture) (*generatedPassword, error) {
+	role := userFixture.Role
+	if role == "" && len(userFixture.Roles) > 0 {
+		role = userFixture.Roles[0]
+	}
 
-// // SeedSuperAdmin akan menambahkan user super admin ke database
-// func SeedSuperAdmin(db *gorm.DB, password string) error {
-// 	// Cek apakah sudah ada user super admin
-// 	var user models.User
-// 	if err := db.Where("username = ?", "superadmin@mail.com").First(&user).Error; err == nil {
-// 		// Jika ditemukan, berarti superadmin sudah ada
-// 		log.Println("Super Admin sudah ada!")
-// 		return nil
-// 	}
+	var generated *generatedPassword
+	var user models.User
+	err := tx.Where("username = ?", userFixture.Username).First(&user).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		password := ""
+		if userFixture.PasswordEnv != "" {
+			password = os.Getenv(userFixture.PasswordEnv)
+		}
+		if password == "" {
+			if password, err = randomPassword(); err != nil {
+				return nil, err
+			}
+			generated = &generatedPassword{Username: userFixture.Username, Password: password}
+		}
 
-// 	// Seed Role Superadmin dan Admin
-// 	superAdminRole := models.Role{Name: "superadmin"}
-// 	if err := db.Create(&superAdminRole).Error; err != nil {
-// 		return err
-// 	}
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
 
-// 	adminRole := models.Role{Name: "admin"}
-// 	if err := db.Create(&adminRole).Error; err != nil {
-// 		return err
-// 	}
+		user = models.User{
+			Username: userFixture.Username,
+			Password: string(hashedPassword),
+			Role:     role,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case role != "" && user.Role != role:
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"role":    role,
+			"version": gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
 
-// 	// Tambahkan permissions ke database
-// 	permissions := []models.Permission{
-// 		{Name: "create_user"},
-// 		{Name: "edit_user"},
-// 		{Name: "delete_user"},
-// 		{Name: "view_user"},
-// 	}
-// 	if err := db.Create(&permissions).Error; err != nil {
-// 		return err
-// 	}
+	if userFixture.Roles != nil {
+		// Nama role yang ditulis dua kali di fixture hanya dihitung sekali
+		names := uniqueNames(userFixture.Roles)
+		var roles []models.Role
+		if err := tx.Where("name IN ?", names).Find(&roles).Error; err != nil {
+			return nil, err
+		}
+		if len(roles) != len(names) {
+			return nil, fmt.Errorf("user %s: some of roles %v do not exist", userFixture.Username, userFixture.Roles)
+		}
+		if err := tx.Model(&user).Association("Roles").Replace(&roles); err != nil {
+			return nil, err
+		}
+	}
 
-// 	// Kaitkan semua permissions dengan superAdminRole
-// 	if err := db.Model(&superAdminRole).Association("Permissions").Append(&permissions); err != nil {
-// 		return err
-// 	}
+	if userFixture.Permissions != nil {
+		permissions, err := findPermissions(tx, userFixture.Permissions)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: %w", userFixture.Username, err)
+		}
+		if err := tx.Model(&user).Association("Permissions").Replace(&permissions); err != nil {
+			return nil, err
+		}
+	}
 
-// 	// Membuat user superadmin dengan role dan permission yang sudah ada
-// 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-// 	if err != nil {
-// 		return err
-// 	}
+	return generated, nil
+}
 
-// 	superAdmin := models.User{
-// 		Username:    "superadmin@mail.com",
-// 		Password:    string(hashedPassword),
-// 		Role:        "superadmin",
-// 		Roles:       []models.Role{superAdminRole}, // Set role superadmin
-// 		Permissions: permissions,                   // Set permissions langsung
-// 	}
+// findPermissions mencari permission berdasarkan nama; "*" berarti semua permission
+func findPermissions(tx *gorm.DB, names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	for _, name := range names {
+		if name == allPermissions {
+			err := tx.Order("id").Find(&permissions).Error
+			return permissions, err
+		}
+	}
 
-// 	// Menyimpan user super admin ke dalam database
-// 	if err := db.Create(&superAdmin).Error; err != nil {
-// 		return err
-// 	}
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	unique := uniqueNames(names)
+	if err := tx.Where("name IN ?", unique).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(unique) {
+		return nil, fmt.Errorf("some of permissions %v do not exist", names)
+	}
+	return permissions, nil
+}
 
-// 	// **Hapus** penambahan role dan permission berulang pada user superAdmin
+// uniqueNames menghapus nama duplikat dengan tetap menjaga urutan
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
 
-// 	log.Println("Super Admin berhasil dibuat!")
-// 	return nil
-// }
+// randomPassword membuat password acak 24 karakter
+func randomPassword() (string, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
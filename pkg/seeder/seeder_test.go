@@ -0,0 +1,61 @@
+package seeder
+
+import (
+	"context"
+	"io"
+	"project/internal/models"
+	"project/internal/testutil"
+	"testing"
+)
+
+func TestSeederDuplicateNames(t *testing.T) {
+	db := testutil.NewDB(t)
+	fixtures := []Fixture{{
+		Name:        "base",
+		Permissions: []string{"users.read", "users.write"},
+		Roles: []RoleFixture{
+			{Name: "admin", Permissions: []string{"users.read", "users.write", "users.read"}},
+			{Name: "viewer", Permissions: []string{"users.read"}},
+		},
+		Users: []UserFixture{{
+			Username: "dup@mail.com",
+			Roles:    []string{"admin", "viewer", "admin"},
+		}},
+	}}
+
+	// Dijalankan dua kali: seed harus idempoten
+	for i := 0; i < 2; i++ {
+		if err := New(db, fixtures, io.Discard).Run(context.Background(), "test"); err != nil {
+			t.Fatalf("Run %d: %v", i+1, err)
+		}
+	}
+
+	var user models.User
+	if err := db.Preload("Roles").Where("username = ?", "dup@mail.com").First(&user).Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if user.Role != "admin" || len(user.Roles) != 2 {
+		t.Errorf("user role %q with %d roles, want admin with 2 roles", user.Role, len(user.Roles))
+	}
+
+	var admin models.Role
+	if err := db.Preload("Permissions").Where("name = ?", "admin").First(&admin).Error; err != nil {
+		t.Fatalf("load role: %v", err)
+	}
+	if len(admin.Permissions) != 2 {
+		t.Errorf("admin permissions = %d, want 2", len(admin.Permissions))
+	}
+}
+
+func TestSeederUnknownRole(t *testing.T) {
+	db := testutil.NewDB(t)
+	fixtures := []Fixture{{
+		Name:  "base",
+		Roles: []RoleFixture{{Name: "admin"}},
+		Users: []UserFixture{{Username: "unknown@mail.com", Roles: []string{"admin", "missing", "admin"}}},
+	}}
+
+	if err := New(db, fixtures, io.Discard).Run(context.Background(), "test"); err == nil {
+		t.Fatal("Run with an unknown role succeeded, want an error")
+	}
+}
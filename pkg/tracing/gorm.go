@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey adalah key di gorm.Statement.Settings untuk menyimpan span yang sedang berjalan
+const gormSpanKey = "app:tracing_span"
+
+// GormPlugin adalah plugin gorm yang membuat span untuk setiap query.
+// SQL dicatat tanpa nilai parameter.
+type GormPlugin struct {
+	tracer trace.Tracer
+}
+
+// NewGormPlugin membuat plugin; pasang dengan db.Use(tracing.NewGormPlugin())
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{tracer: Tracer("gorm")}
+}
+
+// Name - nama plugin gorm
+func (p *GormPlugin) Name() string {
+	return "app:tracing"
+}
+
+// Initialize mendaftarkan callback sebelum dan sesudah setiap jenis query
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	operations := []struct {
+		name     string
+		before   func(name string, fn func(*gorm.DB)) error
+		after    func(name string, fn func(*gorm.DB)) error
+		spanName string
+	}{
+		{"create", callbacks.Create().Before("*").Register, callbacks.Create().After("*").Register, "gorm.create"},
+		{"query", callbacks.Query().Before("*").Register, callbacks.Query().After("*").Register, "gorm.query"},
+		{"update", callbacks.Update().Before("*").Register, callbacks.Update().After("*").Register, "gorm.update"},
+		{"delete", callbacks.Delete().Before("*").Register, callbacks.Delete().After("*").Register, "gorm.delete"},
+		{"row", callbacks.Row().Before("*").Register, callbacks.Row().After("*").Register, "gorm.row"},
+		{"raw", callbacks.Raw().Before("*").Register, callbacks.Raw().After("*").Register, "gorm.raw"},
+	}
+
+	for _, operation := range operations {
+		spanName := operation.spanName
+		if err := operation.before("app:tracing_before_"+operation.name, func(tx *gorm.DB) { p.before(tx, spanName) }); err != nil {
+			return err
+		}
+		if err := operation.after("app:tracing_after_"+operation.name, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *GormPlugin) before(tx *gorm.DB, spanName string) {
+	ctx := tx.Statement.Context
+	if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		// Query di luar request (migrasi, monitor) tidak membuat trace baru
+		return
+	}
+	_, span := p.tracer.Start(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemKey.String(tx.Dialector.Name())),
+	)
+	tx.Statement.Settings.Store(gormSpanKey, span)
+}
+
+func (p *GormPlugin) after(tx *gorm.DB) {
+	value, ok := tx.Statement.Settings.LoadAndDelete(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBQueryText(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.RowsAffected),
+	)
+	if tx.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+	}
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporter yang didukung (nilai logging.trace_exporter di config)
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// instrumentationPrefix adalah prefix nama tracer untuk setiap komponen
+const instrumentationPrefix = "project/"
+
+// Options mengatur tracer provider
+type Options struct {
+	// Exporter: none (default), otlp atau stdout
+	Exporter string
+	// Endpoint OTLP/HTTP (host:port atau URL), misalnya APM server di localhost:8200
+	Endpoint string
+	// Insecure mengirim OTLP lewat HTTP biasa (tanpa TLS)
+	Insecure bool
+	// ServiceName dicatat sebagai service.name di setiap span
+	ServiceName string
+	// SampleRatio adalah rasio trace baru yang direkam (0..1); trace dari upstream
+	// mengikuti keputusan sampling parent
+	SampleRatio float64
+}
+
+// Setup memasang tracer provider global dan propagator W3C (traceparent, baggage).
+// Propagator selalu dipasang agar traceparent tetap diteruskan walaupun exporter "none".
+// Fungsi yang dikembalikan mengirim span yang tersisa lalu mematikan provider.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(opts.Exporter) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlpExporter(ctx, opts)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = "user-management-api"
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// otlpExporter membuat exporter OTLP/HTTP; Endpoint boleh berupa URL lengkap atau host:port
+func otlpExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, error) {
+	var clientOptions []otlptracehttp.Option
+	if strings.Contains(opts.Endpoint, "://") {
+		clientOptions = append(clientOptions, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	} else {
+		clientOptions = append(clientOptions, otlptracehttp.WithEndpoint(opts.Endpoint))
+	}
+	if opts.Insecure {
+		clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(ctx, clientOptions...)
+}
+
+// Tracer mengembalikan tracer untuk komponen (misalnya "http", "service", "gorm")
+func Tracer(component string) trace.Tracer {
+	return otel.Tracer(instrumentationPrefix + component)
+}